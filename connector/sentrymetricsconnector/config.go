@@ -17,6 +17,8 @@ package sentrymetricsconnector
 type Config struct {
 	SentryMeasurementsCfg SentryMeasurementsConfig `mapstructure:"sentry_measurements"`
 	SentryEventCountCfg   SentryEventCountConfig   `mapstructure:"sentry_events"`
+	SentrySessionsCfg     SentrySessionsConfig     `mapstructure:"sentry_sessions"`
+	SentryMobileCfg       SentryMobileConfig       `mapstructure:"sentry_mobile"`
 }
 
 type SentryMeasurementsConfig struct {
@@ -34,6 +36,15 @@ type SentryEventCountConfig struct {
 	Labels map[string]string `mapstructure:"labels"`
 }
 
+type SentrySessionsConfig struct {
+	DurationBuckets []float64 `mapstructure:"duration_buckets"`
+}
+
+type SentryMobileConfig struct {
+	AppStartBuckets    []float64 `mapstructure:"app_start_buckets"`
+	FramesRatioBuckets []float64 `mapstructure:"frames_ratio_buckets"`
+}
+
 func (c *Config) Validate() error {
 	return nil
 }
@@ -47,6 +47,9 @@ type sentrymetrics struct {
 	defaultMeasurementsBuckets []float64
 	measurementsLabels         map[string]map[string]string
 	defaultMeasurementsLabels  map[string]string
+	sessionDurationHist        *metrics.CustomHistogram
+	appStartHist               *metrics.CustomHistogram
+	framesRatioHist            *metrics.CustomHistogram
 }
 
 func CreateSentryMetricsConnector(config *Config, metricsConsumer consumer.Metrics, set connector.Settings) *sentrymetrics {
@@ -55,6 +58,9 @@ func CreateSentryMetricsConnector(config *Config, metricsConsumer consumer.Metri
 	result.metricsConsumer = metricsConsumer
 	result.logger = set.Logger
 	result.measurementsHist = metrics.NewCustomHistogram(set.Logger)
+	result.sessionDurationHist = metrics.NewNamedCustomHistogram(set.Logger, "sentry_session_duration", "The metric shows durations of the ended sessions", "second")
+	result.appStartHist = metrics.NewNamedCustomHistogram(set.Logger, "sentry_app_start_duration", "The metric shows durations of the mobile application starts", "millisecond")
+	result.framesRatioHist = metrics.NewNamedCustomHistogram(set.Logger, "sentry_frames_ratio", "The metric shows ratios of the slow and frozen frames to the total frames of the mobile transactions", "ratio")
 	result.defaultMeasurementsBuckets = config.SentryMeasurementsCfg.DefaultBuckets
 	result.measurementsBuckets = make(map[string][]float64)
 	for k, v := range config.SentryMeasurementsCfg.Custom {
@@ -83,6 +89,8 @@ func (c *sentrymetrics) ConsumeTraces(ctx context.Context, td ptrace.Traces) err
 	c.calculateSessionCountMetric(scopeMetrics.Metrics().AppendEmpty(), td)
 	c.calculateEventCountMetric(scopeMetrics.Metrics().AppendEmpty(), td)
 	c.calculateMeasurementsMetric(scopeMetrics.Metrics().AppendEmpty(), td)
+	c.calculateSessionMetrics(scopeMetrics.Metrics().AppendEmpty(), scopeMetrics.Metrics().AppendEmpty(), td)
+	c.calculateMobileMetrics(scopeMetrics.Metrics().AppendEmpty(), scopeMetrics.Metrics().AppendEmpty(), td)
 	return c.metricsConsumer.ConsumeMetrics(ctx, countMetrics)
 }
 
@@ -125,6 +133,9 @@ func (c *sentrymetrics) calculateSessionCountMetric(metric pmetric.Metric, td pt
 				}
 				dataPoint := dataPoints.AppendEmpty()
 				dataPoint.Attributes().PutStr("service_name", serviceNameStr)
+				if tenant, ok := rs.Resource().Attributes().Get("tenant.id"); ok {
+					dataPoint.Attributes().PutStr("tenant", tenant.AsString())
+				}
 				dataPoint.SetDoubleValue(1.0)
 			}
 		}
@@ -133,6 +144,123 @@ func (c *sentrymetrics) calculateSessionCountMetric(metric pmetric.Metric, td pt
 	return nil
 }
 
+// calculateSessionMetrics counts the ended sessions by outcome and observes their durations.
+// Only the sessions, which are tracked by sentry receiver from the start to the end, have the outcome.
+func (c *sentrymetrics) calculateSessionMetrics(countMetric pmetric.Metric, durationMetric pmetric.Metric, td ptrace.Traces) {
+	countMetric.SetName("sentry_session_count")
+	countMetric.SetDescription("The metric counts the ended sessions by outcome")
+	sum := countMetric.SetEmptySum()
+	sum.SetAggregationTemporality(1)
+	sum.SetIsMonotonic(true)
+	dataPoints := sum.DataPoints()
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		ilss := rs.ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				outcome, ok := span.Attributes().Get("session.outcome")
+				if !ok {
+					continue
+				}
+				labels := c.getLabels(span, rs.Resource(), map[string]string{
+					"service_name": "service.name",
+					"tenant":       "tenant.id",
+					"release":      "service.version",
+					"environment":  "deployment.environment",
+				})
+				if duration, ok := span.Attributes().Get("session.duration"); ok {
+					c.sessionDurationHist.ObserveSingle(duration.Double(), c.config.SentrySessionsCfg.DurationBuckets, labels)
+				}
+				dataPoint := dataPoints.AppendEmpty()
+				for labelName, labelValue := range labels {
+					dataPoint.Attributes().PutStr(labelName, labelValue)
+				}
+				dataPoint.Attributes().PutStr("outcome", outcome.AsString())
+				dataPoint.SetDoubleValue(1.0)
+			}
+		}
+	}
+	c.sessionDurationHist.UpdateDataPoints(durationMetric)
+}
+
+// calculateMobileMetrics observes app start durations (app_start_cold, app_start_warm measurements) and
+// ratios of slow and frozen frames (frames_slow, frames_frozen to frames_total) of the mobile transactions
+func (c *sentrymetrics) calculateMobileMetrics(appStartMetric pmetric.Metric, framesMetric pmetric.Metric, td ptrace.Traces) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		ilss := rs.ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				envelopType, ok := span.Attributes().Get("sentry.envelop.type.int")
+				if !ok || envelopType.Int() != models.ENVELOP_TYPE_TRANSACTION {
+					continue
+				}
+				measurements, ok := span.Attributes().Get("measurements")
+				if !ok || measurements.Type() != pcommon.ValueTypeMap {
+					continue
+				}
+				labels := c.getLabels(span, rs.Resource(), map[string]string{
+					"service_name": "service.name",
+					"tenant":       "tenant.id",
+					"release":      "service.version",
+					"environment":  "deployment.environment",
+					"os_name":      "os.name",
+				})
+				for startType, measurementName := range map[string]string{"cold": "app_start_cold", "warm": "app_start_warm"} {
+					if value, unit, ok := getMeasurement(measurements.Map(), measurementName); ok {
+						c.appStartHist.ObserveSingle(normalizeUnit(value, unit), c.config.SentryMobileCfg.AppStartBuckets, withLabel(labels, "start_type", startType))
+					}
+				}
+				total, _, ok := getMeasurement(measurements.Map(), "frames_total")
+				if !ok || total <= 0 {
+					continue
+				}
+				for framesType, measurementName := range map[string]string{"slow": "frames_slow", "frozen": "frames_frozen"} {
+					if value, _, ok := getMeasurement(measurements.Map(), measurementName); ok {
+						c.framesRatioHist.ObserveSingle(value/total, c.config.SentryMobileCfg.FramesRatioBuckets, withLabel(labels, "frames", framesType))
+					}
+				}
+			}
+		}
+	}
+	c.appStartHist.UpdateDataPoints(appStartMetric)
+	c.framesRatioHist.UpdateDataPoints(framesMetric)
+}
+
+// getMeasurement returns the value and the unit of the measurement from "measurements" span attribute
+func getMeasurement(measurements pcommon.Map, name string) (float64, string, bool) {
+	measurement, ok := measurements.Get(name)
+	if !ok || measurement.Type() != pcommon.ValueTypeMap {
+		return 0, "", false
+	}
+	value, ok := measurement.Map().Get("value")
+	if !ok {
+		return 0, "", false
+	}
+	var unit string
+	if unitValue, ok := measurement.Map().Get("unit"); ok {
+		unit = unitValue.AsString()
+	}
+	return value.Double(), unit, true
+}
+
+// withLabel returns the copy of the labels with the additional label
+func withLabel(labels map[string]string, name string, value string) map[string]string {
+	result := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		result[k] = v
+	}
+	result[name] = value
+	return result
+}
+
 func (c *sentrymetrics) calculateEventCountMetric(metric pmetric.Metric, td ptrace.Traces) error {
 	metric.SetName("sentry_event_count")
 	metric.SetDescription("The metric counts total number of events by level")
@@ -160,7 +288,7 @@ func (c *sentrymetrics) calculateEventCountMetric(metric pmetric.Metric, td ptra
 				}
 				dataPoint := dataPoints.AppendEmpty()
 				dataPoint.SetDoubleValue(1.0)
-				labels := c.getLabels(span, labelsToExtract)
+				labels := c.getLabels(span, rs.Resource(), labelsToExtract)
 				for labelName, labelValue := range labels {
 					dataPoint.Attributes().PutStr(labelName, labelValue)
 				}
@@ -185,7 +313,8 @@ func (c *sentrymetrics) calculateMeasurementsMetric(metric pmetric.Metric, td pt
 				if ok {
 					envelopTypeInt = envelopType.Int()
 				}
-				if envelopTypeInt != models.ENVELOP_TYPE_TRANSACTION {
+				// standalone spans (e.g. INP and CLS web vitals) carry measurements like transactions do
+				if envelopTypeInt != models.ENVELOP_TYPE_TRANSACTION && envelopTypeInt != models.ENVELOP_TYPE_SPAN {
 					continue
 				}
 				var measurementsCommonMap pcommon.Map
@@ -193,13 +322,13 @@ func (c *sentrymetrics) calculateMeasurementsMetric(metric pmetric.Metric, td pt
 				if ok {
 					measurementsCommonMap = measurements.Map()
 				}
-				configurableLabels := c.getConfigurableMeasurementLabels(span, "")
+				configurableLabels := c.getConfigurableMeasurementLabels(span, rs.Resource(), "")
 				c.logger.Sugar().Debugf("SentryMetricsConnector : GOT TRANSACTION with measurements size=%v, configurableLabels=%+v", measurementsCommonMap.Len(), configurableLabels)
 				measurementsCommonMap.Range(func(k string, v pcommon.Value) bool {
 					labels := make(map[string]string)
 					labels["type"] = k
 					if c.measurementsLabels[k] != nil {
-						customConfigurableLabels := c.getConfigurableMeasurementLabels(span, k)
+						customConfigurableLabels := c.getConfigurableMeasurementLabels(span, rs.Resource(), k)
 						for k, v := range customConfigurableLabels {
 							labels[k] = v
 						}
@@ -227,10 +356,13 @@ func (c *sentrymetrics) calculateMeasurementsMetric(metric pmetric.Metric, td pt
 					}
 					return true
 				})
+				if envelopTypeInt != models.ENVELOP_TYPE_TRANSACTION {
+					continue
+				}
 				labels := make(map[string]string)
 				labels["type"] = "transaction_duration"
 				if c.measurementsLabels["transaction_duration"] != nil {
-					customConfigurableLabels := c.getConfigurableMeasurementLabels(span, "transaction_duration")
+					customConfigurableLabels := c.getConfigurableMeasurementLabels(span, rs.Resource(), "transaction_duration")
 					for k, v := range customConfigurableLabels {
 						labels[k] = v
 					}
@@ -251,7 +383,7 @@ func (c *sentrymetrics) calculateMeasurementsMetric(metric pmetric.Metric, td pt
 	return nil
 }
 
-func (c *sentrymetrics) getConfigurableMeasurementLabels(span ptrace.Span, measurementType string) map[string]string {
+func (c *sentrymetrics) getConfigurableMeasurementLabels(span ptrace.Span, resource pcommon.Resource, measurementType string) map[string]string {
 	var labelsToExtract map[string]string
 	if measurementType == "" {
 		labelsToExtract = c.defaultMeasurementsLabels
@@ -264,13 +396,18 @@ func (c *sentrymetrics) getConfigurableMeasurementLabels(span ptrace.Span, measu
 		c.logger.Sugar().Debugf("Set default labelsToExtract %+v for measurement %v", labelsToExtract, measurementType)
 	}
 
-	return c.getLabels(span, labelsToExtract)
+	return c.getLabels(span, resource, labelsToExtract)
 }
 
-func (c *sentrymetrics) getLabels(span ptrace.Span, labelsToExtract map[string]string) map[string]string {
+// getLabels takes the label values from the span attributes. If the span does not have the attribute,
+// the attribute of the resource is used (e.g. tenant.id, which is set by sentry receiver on the resource).
+func (c *sentrymetrics) getLabels(span ptrace.Span, resource pcommon.Resource, labelsToExtract map[string]string) map[string]string {
 	result := make(map[string]string)
 	for labelName, labelPath := range labelsToExtract {
 		labelValue, ok := span.Attributes().Get(labelPath)
+		if !ok {
+			labelValue, ok = resource.Attributes().Get(labelPath)
+		}
 		if ok {
 			result[labelName] = labelValue.AsString()
 		} else {
@@ -39,6 +39,13 @@ func createDefaultConfig() component.Config {
 		SentryMeasurementsCfg: SentryMeasurementsConfig{
 			DefaultBuckets: []float64{100, 1000, 5000},
 		},
+		SentrySessionsCfg: SentrySessionsConfig{
+			DurationBuckets: []float64{1, 10, 30, 60, 300, 600, 1800, 3600, 7200},
+		},
+		SentryMobileCfg: SentryMobileConfig{
+			AppStartBuckets:    []float64{100, 250, 500, 1000, 2000, 3000, 5000, 10000},
+			FramesRatioBuckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 0.75, 1},
+		},
 	}
 }
 
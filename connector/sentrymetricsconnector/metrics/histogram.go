@@ -24,8 +24,11 @@ import (
 
 type CustomHistogram struct {
 	sync.RWMutex
-	stateMap map[string]*CurrentHistogramState
-	logger   *zap.Logger
+	stateMap    map[string]*CurrentHistogramState
+	logger      *zap.Logger
+	name        string
+	description string
+	unit        string
 }
 
 type CurrentHistogramState struct {
@@ -37,9 +40,16 @@ type CurrentHistogramState struct {
 }
 
 func NewCustomHistogram(logger *zap.Logger) *CustomHistogram {
+	return NewNamedCustomHistogram(logger, "sentry_measurements_statistic", "The metric shows sentry measurements statistic", "millisecond")
+}
+
+func NewNamedCustomHistogram(logger *zap.Logger, name string, description string, unit string) *CustomHistogram {
 	customHistogram := CustomHistogram{}
 	customHistogram.stateMap = make(map[string]*CurrentHistogramState)
 	customHistogram.logger = logger
+	customHistogram.name = name
+	customHistogram.description = description
+	customHistogram.unit = unit
 	return &customHistogram
 }
 
@@ -74,9 +84,9 @@ func (h *CustomHistogram) ObserveSingle(val float64, bucketList []float64, label
 func (h *CustomHistogram) UpdateDataPoints(metric pmetric.Metric) {
 	h.Lock()
 	defer h.Unlock()
-	metric.SetName("sentry_measurements_statistic")
-	metric.SetDescription("The metric shows sentry measurements statistic")
-	metric.SetUnit("millisecond")
+	metric.SetName(h.name)
+	metric.SetDescription(h.description)
+	metric.SetUnit(h.unit)
 	hist := metric.SetEmptyHistogram()
 	hist.SetAggregationTemporality(2)
 	dataPoints := hist.DataPoints()
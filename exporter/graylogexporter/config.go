@@ -28,6 +28,7 @@ type GELFFieldMapping struct {
 	ShortMessage string `mapstructure:"short-message"`
 	FullMessage  string `mapstructure:"full-message"`
 	Level        string `mapstructure:"level"`
+	Tenant       string `mapstructure:"tenant"`
 }
 
 type Config struct {
@@ -47,6 +48,7 @@ func getDefaultGELFFields() *GELFFieldMapping {
 		ShortMessage: "short-message",
 		FullMessage:  "full-message",
 		Level:        "info",
+		Tenant:       "tenant.id",
 	}
 }
 
@@ -145,6 +145,9 @@ func (le *grayLogExporter) logRecordToMessage(logRecord plog.LogRecord, resource
 		message,
 	)
 	hostname := le.getMappedValue(le.config.GELFMapping.Host, attributes, logRecord.Attributes())
+	if tenant := le.getTenant(attributes, logRecord.Attributes(), resourceAttrs); tenant != "" {
+		extra["tenant"] = tenant
+	}
 
 	return &graylog.Message{
 		Version:      le.config.GELFMapping.Version,
@@ -255,6 +258,25 @@ func (le *grayLogExporter) getMappedValue(key string, attributes map[string]inte
 	return fmt.Sprintf("%v not found", key)
 }
 
+// getTenant returns the value of the tenant attribute of the log record or of the resource
+func (le *grayLogExporter) getTenant(attributes map[string]interface{}, logAttrs pcommon.Map, resourceAttrs pcommon.Map) string {
+	key := le.config.GELFMapping.Tenant
+	if key == "" {
+		return ""
+	}
+	if val, ok := attributes[key]; ok {
+		return fmt.Sprintf("%v", val)
+	}
+	for _, attrs := range []pcommon.Map{logAttrs, resourceAttrs} {
+		if val, ok := attrs.Get(key); ok {
+			if s, ok := getStringFromPcommonValue(val); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
 func defaultIfEmpty(val, fallback string) string {
 	val = strings.TrimSpace(val)
 	if val == "" || strings.Contains(strings.ToLower(val), "not found") {
@@ -61,6 +61,7 @@ type ATLConfig struct {
 
 type ATLFilter struct { // ArbitraryTracesLoggingFilter
 	ServiceNames []string            `mapstructure:"service-names"`
+	Tenants      []string            `mapstructure:"tenants"`
 	Tags         map[string]string   `mapstructure:"tags"`
 	Mapping      map[string][]string `mapstructure:"mapping"`
 }
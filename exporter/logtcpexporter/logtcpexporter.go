@@ -16,8 +16,8 @@ package logtcpexporter
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -68,14 +68,14 @@ func (lte *logTcpExporter) start(_ context.Context, host component.Host) (err er
 		if err != nil {
 			errMsg := fmt.Sprintf("Error parsing %v port number to uint64 : %+v\n", endpointSplitted[1], err)
 			lte.logger.Error(errMsg)
-			return fmt.Errorf(errMsg)
+			return errors.New(errMsg)
 		}
 	}
 	freezeTime, err := time.ParseDuration(lte.config.SuccessiveSendErrFreezeTime)
 	if err != nil {
 		errMsg := fmt.Sprintf("lte.config.successiveSendErrFreezeTime is not parseable : %+v", err)
 		lte.logger.Error(errMsg)
-		return fmt.Errorf(errMsg)
+		return errors.New(errMsg)
 	}
 	lte.graylogSender = graylog.NewGraylogSender(
 		graylog.Endpoint{
@@ -108,18 +108,23 @@ func (lte *logTcpExporter) pushTraces(ctx context.Context, traces ptrace.Traces)
 
 	rss := traces.ResourceSpans()
 	for i := 0; i < rss.Len(); i++ {
+		resource := rss.At(i).Resource()
+		tenant := getTenant(resource)
 		sss := rss.At(i).ScopeSpans()
 		for j := 0; j < sss.Len(); j++ {
 			spans := sss.At(j).Spans()
 			for k := 0; k < spans.Len(); k++ {
 				span := spans.At(k)
 				if isSentry {
-					if span.Name() == "Event" {
-						lte.sendSentrySpan(span)
+					switch span.Name() {
+					case "Event":
+						lte.sendSentrySpan(span, tenant)
+					case "Feedback":
+						lte.sendSentryFeedbackSpan(span, tenant)
 					}
 				}
 				if lte.spanFilterEnabled {
-					lte.sendArbitraryLoggingSpan(span)
+					lte.sendArbitraryLoggingSpan(span, resource)
 				}
 			}
 		}
@@ -165,8 +170,8 @@ func (lte *logTcpExporter) sendArbitraryLoggingTrace(traces ptrace.Traces) error
 	return nil
 }
 
-func (lte *logTcpExporter) sendArbitraryLoggingSpan(span ptrace.Span) error {
-	alIndex := lte.getATLSpanFilterIndex(span)
+func (lte *logTcpExporter) sendArbitraryLoggingSpan(span ptrace.Span, resource pcommon.Resource) error {
+	alIndex := lte.getATLSpanFilterIndex(span, resource)
 	if alIndex < 0 {
 		lte.logger.Sugar().Debugf("Arbitrary logging : Span is filtered out : alIndex = %v", alIndex)
 		return nil
@@ -279,11 +284,11 @@ func (lte *logTcpExporter) getTimestampAndLevel(traces ptrace.Traces) (time.Time
 	return result, level
 }
 
-func (lte *logTcpExporter) getATLSpanFilterIndex(span ptrace.Span) int {
+func (lte *logTcpExporter) getATLSpanFilterIndex(span ptrace.Span, resource pcommon.Resource) int {
 	spanFilters := lte.config.ATLCfg.SpanFilters
 
 	for filterIndex, filter := range spanFilters {
-		if lte.checkSpanFilterCondition(span, filter) {
+		if lte.checkSpanFilterCondition(span, resource, filter) {
 			lte.logger.Sugar().Debugf("Arbitrary logging : spanfilterIndex = %v ; filter is true", filterIndex)
 			return filterIndex
 		} else {
@@ -294,7 +299,11 @@ func (lte *logTcpExporter) getATLSpanFilterIndex(span ptrace.Span) int {
 	return -1
 }
 
-func (lte *logTcpExporter) checkSpanFilterCondition(span ptrace.Span, filter ATLFilter) bool {
+func (lte *logTcpExporter) checkSpanFilterCondition(span ptrace.Span, resource pcommon.Resource, filter ATLFilter) bool {
+	if !checkTenantFilterCondition(resource, filter) {
+		return false
+	}
+
 	if len(filter.ServiceNames) > 0 {
 		serviceName, ok := span.Attributes().Get("service.name")
 		if !ok {
@@ -323,6 +332,10 @@ func (lte *logTcpExporter) checkResourceFilterCondition(resource pcommon.Resourc
 		return false
 	}
 
+	if !checkTenantFilterCondition(resource, filter) {
+		return false
+	}
+
 	for k, v := range filter.Tags {
 		tag, ok := resource.Attributes().Get(k)
 		if !ok {
@@ -336,6 +349,22 @@ func (lte *logTcpExporter) checkResourceFilterCondition(resource pcommon.Resourc
 	return true
 }
 
+// checkTenantFilterCondition checks the tenant, which is set by sentry receiver on the resource
+func checkTenantFilterCondition(resource pcommon.Resource, filter ATLFilter) bool {
+	if len(filter.Tenants) == 0 {
+		return true
+	}
+	return utils.FindStringIndexInArray(filter.Tenants, getTenant(resource)) >= 0
+}
+
+func getTenant(resource pcommon.Resource) string {
+	tenant, ok := resource.Attributes().Get("tenant.id")
+	if ok {
+		return tenant.AsString()
+	}
+	return ""
+}
+
 func (lte *logTcpExporter) getATLTraceFilterIndex(traces ptrace.Traces) int {
 	traceFilters := lte.config.ATLCfg.TraceFilters
 	for filterIndex, filter := range traceFilters {
@@ -360,7 +389,7 @@ func (lte *logTcpExporter) checkTraceFilterCondition(traces ptrace.Traces, filte
 		for j := 0; j < sss.Len(); j++ {
 			spans := sss.At(j).Spans()
 			for k := 0; k < spans.Len(); k++ {
-				if lte.checkSpanFilterCondition(spans.At(k), filter) {
+				if lte.checkSpanFilterCondition(spans.At(k), rss.At(i).Resource(), filter) {
 					return true
 				}
 			}
@@ -422,8 +451,8 @@ func (lte *logTcpExporter) getTimeFromSpanFields(span ptrace.Span, spanFields []
 	return 0
 }
 
-func (lte *logTcpExporter) sendSentrySpan(span ptrace.Span) error {
-	var spanIdStr, traceIdStr, levelStr, sdkStr, messageStr, fullMessageStr, eventIdStr, versionStr, nameStr, platformStr, userIdStr, transactionStr, categoryStr, urlStr, browserStr string
+func (lte *logTcpExporter) sendSentrySpan(span ptrace.Span, tenant string) error {
+	var spanIdStr, traceIdStr, levelStr, sdkStr, messageStr, fullMessageStr, eventIdStr, versionStr, nameStr, platformStr, userIdStr, transactionStr, categoryStr, urlStr, browserStr, fingerprintStr string
 	var graylogLevel uint
 	var timestampUnix int64
 
@@ -523,6 +552,11 @@ func (lte *logTcpExporter) sendSentrySpan(span ptrace.Span) error {
 		browserStr = browser.AsString()
 	}
 
+	fingerprint, ok := span.Attributes().Get("issue.fingerprint")
+	if ok {
+		fingerprintStr = fingerprint.AsString()
+	}
+
 	timestampParsed := time.Unix(timestampUnix, 0)
 	msg := graylog.Message{
 		Version:      versionStr,
@@ -549,6 +583,12 @@ func (lte *logTcpExporter) sendSentrySpan(span ptrace.Span) error {
 			"browser":     browserStr,
 		},
 	}
+	if fingerprintStr != "" {
+		msg.Extra["issue_fingerprint"] = fingerprintStr
+	}
+	if tenant != "" {
+		msg.Extra["tenant"] = tenant
+	}
 	err := lte.graylogSender.SendToQueue(&msg)
 	if err != nil {
 		lte.logger.Sugar().Errorf("Message with trace_id %v and span_id %v has not been put to the graylog queue: %+v\n", traceIdStr, spanIdStr, err)
@@ -557,22 +597,17 @@ func (lte *logTcpExporter) sendSentrySpan(span ptrace.Span) error {
 	lte.logger.Sugar().Debugf("Message with trace_id %v and span_id %v has been put successfully to the graylog queue\n", traceIdStr, spanIdStr)
 
 	if graylogLevel == 3 {
-		breadcrumbs, ok := span.Attributes().Get("breadcrumbs")
-		if !ok {
-			return nil
-		}
-		breadcrumbsList := breadcrumbs.Slice().AsRaw()
-		for i, breadcrumb := range breadcrumbsList {
-			breadcrumbMap, ok := breadcrumb.(map[string]interface{})
-			if !ok {
-				lte.logger.Sugar().Errorf("Type assertion error : got type %v", reflect.TypeOf(breadcrumb))
+		events := span.Events()
+		for i := 0; i < events.Len(); i++ {
+			breadcrumb := events.At(i)
+			if breadcrumb.Name() != "breadcrumb" {
+				continue
+			}
+			levelB, categoryB, messageB, statusB := getBreadcrumbFields(breadcrumb)
+			var timestampUnixB int64
+			if breadcrumb.Timestamp() != 0 {
+				timestampUnixB = breadcrumb.Timestamp().AsTime().Unix()
 			}
-			levelB, ok := breadcrumbMap["level"].(string)
-			timestampB, ok := breadcrumbMap["timestamp"].(float64)
-			timestampUnixB := int64(timestampB)
-			categoryB, ok := breadcrumbMap["category"].(string)
-			messageB, ok := breadcrumbMap["message"].(string)
-			statusB, ok := breadcrumbMap["status"].(string)
 
 			extra := map[string]string{
 				"span_id":     spanIdStr,
@@ -595,6 +630,12 @@ func (lte *logTcpExporter) sendSentrySpan(span ptrace.Span) error {
 			if statusB != "" {
 				extra["status"] = statusB
 			}
+			if fingerprintStr != "" {
+				extra["issue_fingerprint"] = fingerprintStr
+			}
+			if tenant != "" {
+				extra["tenant"] = tenant
+			}
 
 			msg := graylog.Message{
 				Version:      versionStr,
@@ -616,6 +657,92 @@ func (lte *logTcpExporter) sendSentrySpan(span ptrace.Span) error {
 	return nil
 }
 
+// getBreadcrumbFields returns level, category, message and status of the breadcrumb span event.
+// The message of http and navigation breadcrumbs is built from the breadcrumb data.
+func getBreadcrumbFields(breadcrumb ptrace.SpanEvent) (string, string, string, string) {
+	attrs := breadcrumb.Attributes()
+	getStr := func(key string) string {
+		value, ok := attrs.Get(key)
+		if ok {
+			return value.AsString()
+		}
+		return ""
+	}
+	level := getStr("level")
+	category := getStr("category")
+	message := getStr("message")
+	status := ""
+	if getStr("type") == "http" {
+		message = fmt.Sprintf("%v %v", getStr("data.method"), getStr("data.url"))
+		status = getStr("data.status_code")
+	} else if category == "navigation" {
+		message = fmt.Sprintf("Browser navigation from: %v to: %v", getStr("data.from"), getStr("data.to"))
+	}
+	return level, category, message, status
+}
+
+// sendSentryFeedbackSpan sends the user feedback, received via Sentry feedback widget, as a separate graylog message
+func (lte *logTcpExporter) sendSentryFeedbackSpan(span ptrace.Span, tenant string) error {
+	attrs := span.Attributes()
+	getStr := func(key string) string {
+		value, ok := attrs.Get(key)
+		if ok {
+			return value.AsString()
+		}
+		return ""
+	}
+
+	traceIdStr := span.TraceID().String()
+	spanIdStr := span.SpanID().String()
+	messageStr := getStr("feedback.message")
+	if messageStr == "" {
+		messageStr = "empty_message"
+	}
+	versionStr := getStr("version")
+	if versionStr == "" {
+		versionStr = "empty_version"
+	}
+	timestamp := span.EndTimestamp().AsTime()
+
+	msg := graylog.Message{
+		Version:      versionStr,
+		Host:         "user_browser",
+		ShortMessage: messageStr,
+		FullMessage:  messageStr,
+		Timestamp:    timestamp.Unix(),
+		Level:        6,
+		Extra: map[string]string{
+			"span_id":             spanIdStr,
+			"trace_id":            traceIdStr,
+			"component":           "frontend",
+			"facility":            "open-telemetry-collector",
+			"category":            getFirst(getStr("category"), "user-feedback"),
+			"sdk":                 getStr("sdk"),
+			"event_id":            getStr("event_id"),
+			"name":                getStr("name"),
+			"platform":            getStr("platform"),
+			"time":                timestamp.Format(time.RFC3339),
+			"user_id":             getStr("user_id"),
+			"url":                 getStr("feedback.url"),
+			"feedback_name":       getStr("feedback.name"),
+			"feedback_email":      getStr("feedback.email"),
+			"feedback_source":     getStr("feedback.source"),
+			"associated_event_id": getStr("feedback.associated_event_id"),
+			"replay_id":           getStr("feedback.replay_id"),
+		},
+	}
+	if tenant != "" {
+		msg.Extra["tenant"] = tenant
+	}
+	err := lte.graylogSender.SendToQueue(&msg)
+	if err != nil {
+		lte.logger.Sugar().Errorf("Feedback message with trace_id %v and span_id %v has not been put to the graylog queue: %+v\n", traceIdStr, spanIdStr, err)
+		return err
+	}
+	lte.logger.Sugar().Debugf("Feedback message with trace_id %v and span_id %v has been put successfully to the graylog queue\n", traceIdStr, spanIdStr)
+	return nil
+}
+
 func getFirst(strings ...string) string {
 	for _, str := range strings {
 		if str != "" {
@@ -0,0 +1,116 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/attachments"
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// storeAttachments saves the attachments of the envelope to the attachments store.
+// The attachments are keyed by event_id of the envelope header.
+func (sr *sentrytraceReceiver) storeAttachments(ctx context.Context, envlp *models.EnvelopEventParseResult) {
+	if len(envlp.Attachments) == 0 {
+		return
+	}
+	if sr.attachmentStore == nil {
+		sr.logger.Sugar().Debugf("SentryReceiver : %v attachments are skipped, because attachments.directory is not configured", len(envlp.Attachments))
+		for range envlp.Attachments {
+			sr.telemetry.recordSkippedItem(ctx, "attachment", skipReasonAttachmentsDisabled)
+		}
+		return
+	}
+	eventId := removeHyphens(envlp.EnvelopEventHeader.EventID)
+	if eventId == "" {
+		sr.logger.Sugar().Debugf("SentryReceiver : %v attachments are skipped, because the envelope has no event_id", len(envlp.Attachments))
+		for range envlp.Attachments {
+			sr.telemetry.recordSkippedItem(ctx, "attachment", skipReasonNoEventId)
+		}
+		return
+	}
+	totalSize := 0
+	for _, attachment := range envlp.Attachments {
+		size := len(attachment.Data)
+		if size > sr.config.AttachmentsCfg.MaxAttachmentSize {
+			sr.logger.Sugar().Warnf("SentryReceiver : Attachment %v of event %v is skipped : size %v is greater than %v", attachment.Filename, eventId, size, sr.config.AttachmentsCfg.MaxAttachmentSize)
+			sr.telemetry.recordSkippedItem(ctx, "attachment", skipReasonTooLarge)
+			continue
+		}
+		if totalSize+size > sr.config.AttachmentsCfg.MaxEventAttachmentsSize {
+			sr.logger.Sugar().Warnf("SentryReceiver : Attachment %v of event %v is skipped : total size of the event attachments is greater than %v", attachment.Filename, eventId, sr.config.AttachmentsCfg.MaxEventAttachmentsSize)
+			sr.telemetry.recordSkippedItem(ctx, "attachment", skipReasonTooLarge)
+			continue
+		}
+		location, err := sr.attachmentStore.Save(eventId, attachment.Filename, attachment.Data)
+		if errors.Is(err, attachments.ErrStoreFull) {
+			sr.logger.Sugar().Warnf("SentryReceiver : Attachment %v of event %v is skipped : total size of the stored attachments reached %v", attachment.Filename, eventId, sr.config.AttachmentsCfg.MaxTotalSize)
+			sr.telemetry.recordSkippedItem(ctx, "attachment", skipReasonStoreFull)
+			continue
+		}
+		if err != nil {
+			sr.logger.Sugar().Errorf("SentryReceiver : Error saving attachment %v of event %v : %+v", attachment.Filename, eventId, err)
+			sr.telemetry.recordSkippedItem(ctx, "attachment", skipReasonStoreError)
+			continue
+		}
+		totalSize += size
+		envlp.StoredAttachments = append(envlp.StoredAttachments, models.StoredAttachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Location:    location,
+			Size:        size,
+		})
+	}
+}
+
+// putAttachmentsAttribute lists the stored attachments on the span of the event they belong to
+func (sr *sentrytraceReceiver) putAttachmentsAttribute(attrs pcommon.Map, eventId string, envlp *models.EnvelopEventParseResult) {
+	if len(envlp.StoredAttachments) == 0 || removeHyphens(eventId) != removeHyphens(envlp.EnvelopEventHeader.EventID) {
+		return
+	}
+	attachmentsSlice := attrs.PutEmptySlice("sentry.attachments")
+	for _, stored := range envlp.StoredAttachments {
+		attachmentMap := attachmentsSlice.AppendEmpty().SetEmptyMap()
+		attachmentMap.PutStr("filename", stored.Filename)
+		attachmentMap.PutStr("path", stored.Location)
+		attachmentMap.PutStr("content_type", stored.ContentType)
+		attachmentMap.PutInt("size", int64(stored.Size))
+	}
+}
+
+func (sr *sentrytraceReceiver) cleanupAttachments(ctx context.Context) {
+	// the durations are checked by Config.Validate
+	retention, _ := time.ParseDuration(sr.config.AttachmentsCfg.Retention)
+	interval, _ := time.ParseDuration(sr.config.AttachmentsCfg.CleanupInterval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := sr.attachmentStore.DeleteOlderThan(time.Now().Add(-retention))
+			if err != nil {
+				sr.logger.Sugar().Errorf("SentryReceiver : Error removing outdated attachments : %+v", err)
+				continue
+			}
+			sr.logger.Sugar().Debugf("SentryReceiver : Attachments of %v events are removed by retention", removed)
+		}
+	}
+}
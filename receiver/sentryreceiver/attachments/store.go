@@ -0,0 +1,160 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attachments
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrStoreFull is returned by Save, when the attachment doesn't fit into the total size limit of the store
+var ErrStoreFull = errors.New("attachments store is full")
+
+// Store keeps the payload of Sentry attachments, grouped by the id of the event they belong to.
+type Store interface {
+	// Save stores the attachment and returns the location, which can be used to get it back
+	Save(eventId string, filename string, data []byte) (string, error)
+	// DeleteOlderThan removes the attachments of the events stored before the time and returns the number of removed events
+	DeleteOlderThan(t time.Time) (int, error)
+}
+
+type fileSystemStore struct {
+	sync.Mutex
+	directory    string
+	maxTotalSize int64
+	// total size of the stored files
+	totalSize int64
+}
+
+// NewFileSystemStore creates the store, which saves attachments to <directory>/<event_id>/<filename>.
+// The total size of the files in the directory is limited by maxTotalSize bytes.
+func NewFileSystemStore(directory string, maxTotalSize int64) (Store, error) {
+	if err := os.MkdirAll(directory, 0o750); err != nil {
+		return nil, fmt.Errorf("can not create attachments directory %v : %w", directory, err)
+	}
+	totalSize, err := directorySize(directory)
+	if err != nil {
+		return nil, fmt.Errorf("can not read attachments directory %v : %w", directory, err)
+	}
+	return &fileSystemStore{directory: directory, maxTotalSize: maxTotalSize, totalSize: totalSize}, nil
+}
+
+func (s *fileSystemStore) Save(eventId string, filename string, data []byte) (string, error) {
+	eventDirName := sanitizeFilename(eventId, "")
+	if eventDirName == "" {
+		return "", errors.New("attachment without event id can not be stored")
+	}
+	eventDir := filepath.Join(s.directory, eventDirName)
+	name := sanitizeFilename(filename, "attachment")
+	path := filepath.Join(eventDir, name)
+
+	s.Lock()
+	defer s.Unlock()
+	// SDK retries can send the same attachment again, the existing file is overwritten in this case
+	var existingSize int64
+	if info, err := os.Stat(path); err == nil {
+		existingSize = info.Size()
+	}
+	if s.totalSize-existingSize+int64(len(data)) > s.maxTotalSize {
+		return "", ErrStoreFull
+	}
+	if err := os.MkdirAll(eventDir, 0o750); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0o640); err != nil {
+		return "", err
+	}
+	s.totalSize += int64(len(data)) - existingSize
+	return path, nil
+}
+
+func (s *fileSystemStore) DeleteOlderThan(t time.Time) (int, error) {
+	entries, err := os.ReadDir(s.directory)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		deleted, err := s.deleteIfOlderThan(filepath.Join(s.directory, entry.Name()), t)
+		if err != nil {
+			return removed, err
+		}
+		if deleted {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// deleteIfOlderThan removes the directory of the event, if it is not modified since the time.
+// The check and the removal are done under the lock, so that the concurrent Save doesn't change
+// the directory in between, and only the size of the actually removed files is subtracted.
+func (s *fileSystemStore) deleteIfOlderThan(eventDir string, t time.Time) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	info, err := os.Stat(eventDir)
+	if err != nil || !info.ModTime().Before(t) {
+		return false, nil
+	}
+	size, err := directorySize(eventDir)
+	if err != nil {
+		return false, nil
+	}
+	if err := os.RemoveAll(eventDir); err != nil {
+		// some files can be removed before the error
+		remaining, _ := directorySize(eventDir)
+		s.totalSize -= size - remaining
+		return false, err
+	}
+	s.totalSize -= size
+	return true, nil
+}
+
+func directorySize(directory string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(directory, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// sanitizeFilename keeps only the base name, so that the client can not write outside of the directory
+func sanitizeFilename(name string, defaultName string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = filepath.Base(name)
+	if name == "." || name == ".." || name == "/" || name == "" {
+		return defaultName
+	}
+	return name
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// Types of attribute-types setting
+const (
+	attributeTypeString = "string"
+	attributeTypeBool   = "bool"
+	attributeTypeInt    = "int"
+	attributeTypeDouble = "double"
+)
+
+var supportedAttributeTypes = map[string]bool{
+	attributeTypeString: true,
+	attributeTypeBool:   true,
+	attributeTypeInt:    true,
+	attributeTypeDouble: true,
+}
+
+// putJSONAttribute puts the JSON value of the tag or the span data with the type of the JSON value.
+// If the type of the attribute is set in attribute-types, the value is converted to this type,
+// so that the type doesn't depend on the SDK. The value, which can not be converted, keeps its JSON type.
+func (sr *sentrytraceReceiver) putJSONAttribute(attrs pcommon.Map, key string, value interface{}) {
+	if attributeType, ok := sr.config.AttributeTypes[key]; ok && value != nil {
+		converted, err := convertAttributeValue(value, attributeType)
+		if err == nil {
+			value = converted
+		} else {
+			sr.logger.Sugar().Debugf("SentryReceiver : Value of attribute %v can not be converted to %v : %+v", key, attributeType, err)
+		}
+	}
+	putAttributeValue(attrs, key, value)
+}
+
+// convertAttributeValue converts the JSON value to string, bool, int64 or float64 according to the attribute type
+func convertAttributeValue(value interface{}, attributeType string) (interface{}, error) {
+	switch attributeType {
+	case attributeTypeString:
+		switch valTyped := value.(type) {
+		case string:
+			return valTyped, nil
+		case json.Number:
+			return valTyped.String(), nil
+		case []interface{}, map[string]interface{}:
+			data, err := json.Marshal(valTyped)
+			return string(data), err
+		}
+		return fmt.Sprintf("%v", value), nil
+	case attributeTypeBool:
+		switch valTyped := value.(type) {
+		case bool:
+			return valTyped, nil
+		case string:
+			return strconv.ParseBool(valTyped)
+		case json.Number:
+			number, err := valTyped.Float64()
+			return number != 0, err
+		}
+	case attributeTypeInt:
+		switch valTyped := value.(type) {
+		case bool:
+			if valTyped {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		case string:
+			return strconv.ParseInt(valTyped, 10, 64)
+		case json.Number:
+			if number, err := valTyped.Int64(); err == nil {
+				return number, nil
+			}
+			number, err := valTyped.Float64()
+			if err != nil {
+				return nil, err
+			}
+			if _, frac := math.Modf(number); frac != 0 {
+				return nil, fmt.Errorf("%v is not an integer", valTyped)
+			}
+			return int64(number), nil
+		case float64:
+			if _, frac := math.Modf(valTyped); frac != 0 {
+				return nil, fmt.Errorf("%v is not an integer", valTyped)
+			}
+			return int64(valTyped), nil
+		}
+	case attributeTypeDouble:
+		switch valTyped := value.(type) {
+		case string:
+			return strconv.ParseFloat(valTyped, 64)
+		case json.Number:
+			return valTyped.Float64()
+		case float64:
+			return valTyped, nil
+		}
+	}
+	return nil, fmt.Errorf("%T value can not be converted", value)
+}
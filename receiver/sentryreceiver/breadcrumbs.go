@@ -0,0 +1,88 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"strings"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	breadcrumbEventName   = "breadcrumb"
+	defaultBreadcrumbType = "default"
+)
+
+// appendBreadcrumbEvents adds the breadcrumbs of the event to the span as span events.
+// The breadcrumbs are filtered by category and only the last breadcrumbs.max-count (0 means no limit) of them are kept.
+func (sr *sentrytraceReceiver) appendBreadcrumbEvents(span ptrace.Span, breadcrumbs []models.Breadcrumb) {
+	filtered := make([]models.Breadcrumb, 0, len(breadcrumbs))
+	for _, breadcrumb := range breadcrumbs {
+		if sr.isBreadcrumbCategoryAllowed(breadcrumb.Category) {
+			filtered = append(filtered, breadcrumb)
+		}
+	}
+	if maxCount := sr.config.BreadcrumbsCfg.MaxCount; maxCount > 0 && len(filtered) > maxCount {
+		span.Attributes().PutInt("breadcrumbs.dropped", int64(len(filtered)-maxCount))
+		filtered = filtered[len(filtered)-maxCount:]
+	}
+
+	for _, breadcrumb := range filtered {
+		event := span.Events().AppendEmpty()
+		event.SetName(breadcrumbEventName)
+		if !breadcrumb.Timestamp.IsZero() {
+			event.SetTimestamp(pcommon.NewTimestampFromTime(breadcrumb.Timestamp.Time()))
+		} else {
+			event.SetTimestamp(span.EndTimestamp())
+		}
+		attrs := event.Attributes()
+		breadcrumbType := breadcrumb.Type
+		if breadcrumbType == "" {
+			breadcrumbType = defaultBreadcrumbType
+		}
+		attrs.PutStr("type", breadcrumbType)
+		putNotEmptyStr(attrs, "category", breadcrumb.Category)
+		putNotEmptyStr(attrs, "level", breadcrumb.Level)
+		putNotEmptyStr(attrs, "message", string(breadcrumb.Message))
+		for k, v := range breadcrumb.Data {
+			putFlattenedAttributes(attrs, "data."+k, v)
+		}
+	}
+}
+
+// isBreadcrumbCategoryAllowed checks the category against breadcrumbs.include-categories and breadcrumbs.exclude-categories.
+// The category pattern can end with "*" to match all categories with the prefix, e.g. "ui.*".
+func (sr *sentrytraceReceiver) isBreadcrumbCategoryAllowed(category string) bool {
+	cfg := sr.config.BreadcrumbsCfg
+	if len(cfg.IncludeCategories) > 0 && !matchesAnyCategory(category, cfg.IncludeCategories) {
+		return false
+	}
+	return !matchesAnyCategory(category, cfg.ExcludeCategories)
+}
+
+func matchesAnyCategory(category string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(category, prefix) {
+				return true
+			}
+		} else if category == pattern {
+			return true
+		}
+	}
+	return false
+}
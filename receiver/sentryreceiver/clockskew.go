@@ -0,0 +1,94 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"time"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// getClockSkew returns the difference between the receive time and sent_at of the envelope,
+// if it exceeds clock-skew.threshold. Otherwise 0 is returned.
+func (sr *sentrytraceReceiver) getClockSkew(envlp *models.EnvelopEventParseResult, receivedAt time.Time) time.Duration {
+	if !sr.config.ClockSkewCfg.Enabled || envlp.EnvelopEventHeader.SentAt == "" {
+		return 0
+	}
+	sentAt, err := time.Parse(time.RFC3339, envlp.EnvelopEventHeader.SentAt)
+	if err != nil {
+		sr.logger.Sugar().Debugf("SentryReceiver : sent_at %v of the envelope is not parseable : %+v", envlp.EnvelopEventHeader.SentAt, err)
+		return 0
+	}
+	// the duration is checked by Config.Validate
+	threshold, _ := time.ParseDuration(sr.config.ClockSkewCfg.Threshold)
+	skew := receivedAt.Sub(sentAt)
+	if skew.Abs() <= threshold {
+		return 0
+	}
+	return skew
+}
+
+// applyClockSkew shifts the timestamps of the spans, span events (e.g. breadcrumbs) and the timestamp attribute
+// of the event by the skew. The original timestamps and the correction are kept in the span attributes.
+func applyClockSkew(td ptrace.Traces, skew time.Duration) {
+	if skew == 0 {
+		return
+	}
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		scopeSpans := td.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			spans := scopeSpans.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				shiftSpan(spans.At(k), skew)
+			}
+		}
+	}
+}
+
+func shiftSpan(span ptrace.Span, skew time.Duration) {
+	attrs := span.Attributes()
+	if _, ok := attrs.Get("clock_skew.correction"); ok {
+		return
+	}
+	putClockSkewAttributes(attrs, skew, span.StartTimestamp(), span.EndTimestamp())
+	span.SetStartTimestamp(shiftTimestamp(span.StartTimestamp(), skew))
+	span.SetEndTimestamp(shiftTimestamp(span.EndTimestamp(), skew))
+	for i := 0; i < span.Events().Len(); i++ {
+		event := span.Events().At(i)
+		event.SetTimestamp(shiftTimestamp(event.Timestamp(), skew))
+	}
+	// the timestamp attribute is used as the time of the message in Graylog
+	if timestamp, ok := attrs.Get("timestamp"); ok && timestamp.Type() == pcommon.ValueTypeDouble {
+		attrs.PutDouble("clock_skew.original_timestamp", timestamp.Double())
+		timestamp.SetDouble(timestamp.Double() + skew.Seconds())
+	}
+}
+
+// putClockSkewAttributes keeps the correction and the original start and end time of the span in the attributes.
+// The span with these attributes is not shifted again by applyClockSkew.
+func putClockSkewAttributes(attrs pcommon.Map, skew time.Duration, originalStart pcommon.Timestamp, originalEnd pcommon.Timestamp) {
+	attrs.PutDouble("clock_skew.correction", skew.Seconds())
+	attrs.PutStr("clock_skew.original_start_time", originalStart.AsTime().UTC().Format(time.RFC3339Nano))
+	attrs.PutStr("clock_skew.original_end_time", originalEnd.AsTime().UTC().Format(time.RFC3339Nano))
+}
+
+func shiftTimestamp(timestamp pcommon.Timestamp, skew time.Duration) pcommon.Timestamp {
+	if timestamp == 0 {
+		return 0
+	}
+	return pcommon.NewTimestampFromTime(timestamp.AsTime().Add(skew))
+}
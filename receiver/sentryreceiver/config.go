@@ -15,6 +15,9 @@
 package sentryreceiver
 
 import (
+	"fmt"
+	"time"
+
 	"go.opentelemetry.io/collector/config/confighttp"
 )
 
@@ -25,8 +28,250 @@ type Config struct {
 	HttpQueryParamExistenceToAttrs []string                 `mapstructure:"http-query-param-existence-to-attrs"`
 	LevelEvaluationStrategy        string                   `mapstructure:"level-evaluation-strategy"`
 	ContextSpanAttributesList      []string                 `mapstructure:"context-span-attributes-list"`
+	StatsdMetricsCfg               StatsdMetricsConfig      `mapstructure:"statsd-metrics"`
+	ScrubbingCfg                   ScrubbingConfig          `mapstructure:"scrubbing"`
+	AttachmentsCfg                 AttachmentsConfig        `mapstructure:"attachments"`
+	TenantCfg                      TenantConfig             `mapstructure:"tenant"`
+	ServiceNamespace               string                   `mapstructure:"service-namespace"`
+	BreadcrumbsCfg                 BreadcrumbsConfig        `mapstructure:"breadcrumbs"`
+	ContextsCfg                    ContextsConfig           `mapstructure:"contexts"`
+	StrictParsing                  bool                     `mapstructure:"strict-parsing"`
+	ProjectsCfg                    ProjectsConfig           `mapstructure:"projects"`
+	ErrorCorrelationCfg            ErrorCorrelationConfig   `mapstructure:"error-correlation"`
+	SessionsCfg                    SessionsConfig           `mapstructure:"sessions"`
+	EventFieldsCfg                 EventFieldsConfig        `mapstructure:"event-fields"`
+	ClockSkewCfg                   ClockSkewConfig          `mapstructure:"clock-skew"`
+	FingerprintingCfg              FingerprintingConfig     `mapstructure:"fingerprinting"`
+	AttributeTypes                 map[string]string        `mapstructure:"attribute-types"`
+	RequestCfg                     RequestConfig            `mapstructure:"request"`
+	UserCfg                        UserConfig               `mapstructure:"user"`
+	DeduplicationCfg               DeduplicationConfig      `mapstructure:"deduplication"`
+}
+
+type ScrubbingConfig struct {
+	Replacement   string   `mapstructure:"replacement"`
+	MaskEmails    bool     `mapstructure:"mask-emails"`
+	SensitiveKeys []string `mapstructure:"sensitive-keys"`
+}
+
+type RequestConfig struct {
+	Headers        []string `mapstructure:"headers"`
+	Cookies        []string `mapstructure:"cookies"`
+	Env            []string `mapstructure:"env"`
+	Data           bool     `mapstructure:"data"`
+	MaxValueLength int      `mapstructure:"max-value-length"`
+}
+
+type StatsdMetricsConfig struct {
+	NamePrefix          string    `mapstructure:"name-prefix"`
+	DistributionBuckets []float64 `mapstructure:"distribution-buckets"`
+}
+
+type AttachmentsConfig struct {
+	Directory               string `mapstructure:"directory"`
+	MaxAttachmentSize       int    `mapstructure:"max-attachment-size"`
+	MaxEventAttachmentsSize int    `mapstructure:"max-event-attachments-size"`
+	MaxTotalSize            int    `mapstructure:"max-total-size"`
+	Retention               string `mapstructure:"retention"`
+	CleanupInterval         string `mapstructure:"cleanup-interval"`
+}
+
+type BreadcrumbsConfig struct {
+	MaxCount          int      `mapstructure:"max-count"`
+	IncludeCategories []string `mapstructure:"include-categories"`
+	ExcludeCategories []string `mapstructure:"exclude-categories"`
+}
+
+type ContextsConfig struct {
+	Enabled  bool     `mapstructure:"enabled"`
+	Include  []string `mapstructure:"include"`
+	Exclude  []string `mapstructure:"exclude"`
+	MaxDepth int      `mapstructure:"max-depth"`
+}
+
+type ProjectsConfig struct {
+	File           string `mapstructure:"file"`
+	ReloadInterval string `mapstructure:"reload-interval"`
+}
+
+type ErrorCorrelationConfig struct {
+	Enabled          bool   `mapstructure:"enabled"`
+	Window           string `mapstructure:"window"`
+	MaxPendingEvents int    `mapstructure:"max-pending-events"`
+}
+
+type SessionsConfig struct {
+	TrackLifecycle bool   `mapstructure:"track-lifecycle"`
+	Timeout        string `mapstructure:"timeout"`
+	MaxSessions    int    `mapstructure:"max-sessions"`
+}
+
+type DeduplicationConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	Ttl       string `mapstructure:"ttl"`
+	MaxMemory int    `mapstructure:"max-memory"`
+}
+
+type EventFieldsConfig struct {
+	Extra          bool `mapstructure:"extra"`
+	Modules        bool `mapstructure:"modules"`
+	Threads        bool `mapstructure:"threads"`
+	DebugMeta      bool `mapstructure:"debug-meta"`
+	MaxDepth       int  `mapstructure:"max-depth"`
+	MaxItems       int  `mapstructure:"max-items"`
+	MaxValueLength int  `mapstructure:"max-value-length"`
+}
+
+type ClockSkewConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	Threshold string `mapstructure:"threshold"`
+}
+
+type FingerprintingConfig struct {
+	Enabled              bool   `mapstructure:"enabled"`
+	Strategy             string `mapstructure:"strategy"`
+	UseClientFingerprint bool   `mapstructure:"use-client-fingerprint"`
+}
+
+type UserConfig struct {
+	Email          bool     `mapstructure:"email"`
+	Username       bool     `mapstructure:"username"`
+	IpAddress      bool     `mapstructure:"ip-address"`
+	Segment        bool     `mapstructure:"segment"`
+	Geo            bool     `mapstructure:"geo"`
+	TrustedProxies []string `mapstructure:"trusted-proxies"`
+}
+
+type TenantConfig struct {
+	Header         string                       `mapstructure:"header"`
+	AllowedTenants []string                     `mapstructure:"allowed-tenants"`
+	ProjectTenants map[string]string            `mapstructure:"project-tenants"`
+	PathSegment    int                          `mapstructure:"path-segment"`
+	DefaultTenant  string                       `mapstructure:"default-tenant"`
+	DefaultQuota   TenantQuotaConfig            `mapstructure:"default-quota"`
+	Quotas         map[string]TenantQuotaConfig `mapstructure:"quotas"`
+}
+
+type TenantQuotaConfig struct {
+	RequestsPerSecond float64 `mapstructure:"requests-per-second"`
+	BytesPerSecond    float64 `mapstructure:"bytes-per-second"`
 }
 
 func (cfg *Config) Validate() error {
+	buckets := cfg.StatsdMetricsCfg.DistributionBuckets
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			return fmt.Errorf("statsd-metrics.distribution-buckets must be sorted in ascending order (actual value is %v)", buckets)
+		}
+	}
+	if cfg.BreadcrumbsCfg.MaxCount < 0 {
+		return fmt.Errorf("breadcrumbs.max-count can not be negative (actual value is %v)", cfg.BreadcrumbsCfg.MaxCount)
+	}
+	if cfg.ContextsCfg.MaxDepth < 0 {
+		return fmt.Errorf("contexts.max-depth can not be negative (actual value is %v)", cfg.ContextsCfg.MaxDepth)
+	}
+	if cfg.EventFieldsCfg.MaxDepth < 0 {
+		return fmt.Errorf("event-fields.max-depth can not be negative (actual value is %v)", cfg.EventFieldsCfg.MaxDepth)
+	}
+	if cfg.EventFieldsCfg.MaxItems < 0 {
+		return fmt.Errorf("event-fields.max-items can not be negative (actual value is %v)", cfg.EventFieldsCfg.MaxItems)
+	}
+	if cfg.EventFieldsCfg.MaxValueLength < 0 {
+		return fmt.Errorf("event-fields.max-value-length can not be negative (actual value is %v)", cfg.EventFieldsCfg.MaxValueLength)
+	}
+	if cfg.RequestCfg.MaxValueLength < 0 {
+		return fmt.Errorf("request.max-value-length can not be negative (actual value is %v)", cfg.RequestCfg.MaxValueLength)
+	}
+	if _, err := parseTrustedProxies(cfg.UserCfg.TrustedProxies); err != nil {
+		return err
+	}
+	if cfg.TenantCfg.PathSegment < 0 {
+		return fmt.Errorf("tenant.path-segment can not be negative (actual value is %v)", cfg.TenantCfg.PathSegment)
+	}
+	if cfg.ProjectsCfg.File != "" {
+		reloadInterval, err := time.ParseDuration(cfg.ProjectsCfg.ReloadInterval)
+		if err != nil {
+			return fmt.Errorf("projects.reload-interval is not parseable : %+v", err)
+		}
+		if reloadInterval <= 0 {
+			return fmt.Errorf("projects.reload-interval must be positive (actual value is %v)", reloadInterval)
+		}
+	}
+	if cfg.ErrorCorrelationCfg.Enabled {
+		window, err := time.ParseDuration(cfg.ErrorCorrelationCfg.Window)
+		if err != nil {
+			return fmt.Errorf("error-correlation.window is not parseable : %+v", err)
+		}
+		if window <= 0 {
+			return fmt.Errorf("error-correlation.window must be positive (actual value is %v)", window)
+		}
+		if cfg.ErrorCorrelationCfg.MaxPendingEvents < 1 {
+			return fmt.Errorf("error-correlation.max-pending-events can not be less than 1 (actual value is %v)", cfg.ErrorCorrelationCfg.MaxPendingEvents)
+		}
+	}
+	if cfg.DeduplicationCfg.Enabled {
+		ttl, err := time.ParseDuration(cfg.DeduplicationCfg.Ttl)
+		if err != nil {
+			return fmt.Errorf("deduplication.ttl is not parseable : %+v", err)
+		}
+		if ttl <= 0 {
+			return fmt.Errorf("deduplication.ttl must be positive (actual value is %v)", ttl)
+		}
+		if cfg.DeduplicationCfg.MaxMemory < 1 {
+			return fmt.Errorf("deduplication.max-memory can not be less than 1 (actual value is %v)", cfg.DeduplicationCfg.MaxMemory)
+		}
+	}
+	if cfg.SessionsCfg.TrackLifecycle {
+		timeout, err := time.ParseDuration(cfg.SessionsCfg.Timeout)
+		if err != nil {
+			return fmt.Errorf("sessions.timeout is not parseable : %+v", err)
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("sessions.timeout must be positive (actual value is %v)", timeout)
+		}
+		if cfg.SessionsCfg.MaxSessions < 1 {
+			return fmt.Errorf("sessions.max-sessions can not be less than 1 (actual value is %v)", cfg.SessionsCfg.MaxSessions)
+		}
+	}
+	for key, attributeType := range cfg.AttributeTypes {
+		if !supportedAttributeTypes[attributeType] {
+			return fmt.Errorf("attribute-types.%v has unsupported type %v", key, attributeType)
+		}
+	}
+	if cfg.FingerprintingCfg.Enabled {
+		if _, ok := groupingStrategies[cfg.FingerprintingCfg.Strategy]; !ok {
+			return fmt.Errorf("fingerprinting.strategy %v is not supported", cfg.FingerprintingCfg.Strategy)
+		}
+	}
+	if cfg.ClockSkewCfg.Enabled {
+		threshold, err := time.ParseDuration(cfg.ClockSkewCfg.Threshold)
+		if err != nil {
+			return fmt.Errorf("clock-skew.threshold is not parseable : %+v", err)
+		}
+		if threshold < 0 {
+			return fmt.Errorf("clock-skew.threshold can not be negative (actual value is %v)", threshold)
+		}
+	}
+	if cfg.AttachmentsCfg.Directory != "" {
+		if cfg.AttachmentsCfg.MaxAttachmentSize < 1 {
+			return fmt.Errorf("attachments.max-attachment-size can not be less than 1 (actual value is %v)", cfg.AttachmentsCfg.MaxAttachmentSize)
+		}
+		if cfg.AttachmentsCfg.MaxEventAttachmentsSize < 1 {
+			return fmt.Errorf("attachments.max-event-attachments-size can not be less than 1 (actual value is %v)", cfg.AttachmentsCfg.MaxEventAttachmentsSize)
+		}
+		if cfg.AttachmentsCfg.MaxTotalSize < 1 {
+			return fmt.Errorf("attachments.max-total-size can not be less than 1 (actual value is %v)", cfg.AttachmentsCfg.MaxTotalSize)
+		}
+		if _, err := time.ParseDuration(cfg.AttachmentsCfg.Retention); err != nil {
+			return fmt.Errorf("attachments.retention is not parseable : %+v", err)
+		}
+		cleanupInterval, err := time.ParseDuration(cfg.AttachmentsCfg.CleanupInterval)
+		if err != nil {
+			return fmt.Errorf("attachments.cleanup-interval is not parseable : %+v", err)
+		}
+		if cleanupInterval <= 0 {
+			return fmt.Errorf("attachments.cleanup-interval must be positive (actual value is %v)", cleanupInterval)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,327 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	conventions "go.opentelemetry.io/collector/semconv/v1.9.0"
+)
+
+// exceptionEventName is the name of the span event for the exception according to the semantic conventions
+const exceptionEventName = "exception"
+
+// heldError is the error event, which waits for the transaction it occurred in
+type heldError struct {
+	spanId           pcommon.SpanID
+	eventId          string
+	exceptionType    string
+	exceptionMessage string
+	timestamp        pcommon.Timestamp
+	deadline         time.Time
+	// spans of the error envelope, which are sent together with the transaction or after the deadline
+	traces ptrace.Traces
+}
+
+// errorCorrelator holds the error events for the correlation window, because the transaction,
+// in which the error occurred, is usually sent in another envelope after the error.
+// The span ids of the recent transactions are kept for the window too, so that the errors,
+// which arrive after their transaction, are correlated as well. Both are keyed by trace id.
+type errorCorrelator struct {
+	sync.Mutex
+	window      time.Duration
+	maxPending  int
+	pending     map[pcommon.TraceID][]*heldError
+	count       int
+	recent      map[pcommon.TraceID]*recentTransaction
+	recentCount int
+}
+
+// recentTransaction contains the span ids of the sent transactions of one trace
+type recentTransaction struct {
+	spanIds  map[pcommon.SpanID]bool
+	deadline time.Time
+}
+
+func newErrorCorrelator(window time.Duration, maxPending int) *errorCorrelator {
+	return &errorCorrelator{
+		window:     window,
+		maxPending: maxPending,
+		pending:    make(map[pcommon.TraceID][]*heldError),
+		recent:     make(map[pcommon.TraceID]*recentTransaction),
+	}
+}
+
+// newHeldError returns the error event of the envelope with the trace id, which it occurred in.
+// nil is returned, if the envelope is not an error event of a trace.
+func (sr *sentrytraceReceiver) newHeldError(envlp *models.EnvelopEventParseResult, td ptrace.Traces, deadline time.Time) (pcommon.TraceID, *heldError) {
+	if envlp.EnvelopType != models.ENVELOP_TYPE_EVENT || len(envlp.Events) != 1 {
+		return pcommon.NewTraceIDEmpty(), nil
+	}
+	event := envlp.Events[0]
+	if !sr.isErrorEvent(event) || event.Contexts.Trace.TraceID == "" || event.Contexts.Trace.SpanID == "" {
+		return pcommon.NewTraceIDEmpty(), nil
+	}
+	traceId := sr.GenerateTraceID(event.Contexts.Trace.TraceID)
+	spanId := sr.GenerateSpanId(event.Contexts.Trace.SpanID)
+	if traceId.IsEmpty() || spanId.IsEmpty() {
+		return pcommon.NewTraceIDEmpty(), nil
+	}
+	held := &heldError{
+		spanId:    spanId,
+		eventId:   event.EventId,
+		timestamp: pcommon.NewTimestampFromTime(event.Timestamp.Time().Add(envlp.ClockSkew)),
+		deadline:  deadline,
+		traces:    td,
+	}
+	if len(event.Exception.Values) > 0 {
+		exception := event.Exception.Values[len(event.Exception.Values)-1]
+		held.exceptionType = exception.Type
+		held.exceptionMessage = string(exception.Value)
+	} else {
+		held.exceptionMessage = string(event.Message)
+	}
+	return traceId, held
+}
+
+// hold keeps the spans of the error event envelope until the transaction arrives or the window expires.
+// It returns false, if the limit of the held events is reached.
+func (ec *errorCorrelator) hold(traceId pcommon.TraceID, held *heldError) bool {
+	ec.Lock()
+	defer ec.Unlock()
+	if ec.count >= ec.maxPending {
+		return false
+	}
+	ec.pending[traceId] = append(ec.pending[traceId], held)
+	ec.count++
+	return true
+}
+
+// correlateLate marks the span of the error event as errored and links it to the span of the transaction,
+// in which the error occurred, if the transaction is already sent within the window. The spans of the sent
+// transaction can not be changed. It returns false, if the transaction is not received yet.
+func (ec *errorCorrelator) correlateLate(traceId pcommon.TraceID, held *heldError, now time.Time) bool {
+	ec.Lock()
+	recent, ok := ec.recent[traceId]
+	found := ok && now.Before(recent.deadline) && recent.spanIds[held.spanId]
+	ec.Unlock()
+	if !found {
+		return false
+	}
+	for i := 0; i < held.traces.ResourceSpans().Len(); i++ {
+		scopeSpans := held.traces.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			spanSlice := scopeSpans.At(j).Spans()
+			for k := 0; k < spanSlice.Len(); k++ {
+				span := spanSlice.At(k)
+				if span.TraceID() != traceId || span.ParentSpanID() != held.spanId {
+					continue
+				}
+				setErrorStatus(span, held)
+				link := span.Links().AppendEmpty()
+				link.SetTraceID(traceId)
+				link.SetSpanID(held.spanId)
+				link.Attributes().PutStr("sentry.link.type", "transaction")
+			}
+		}
+	}
+	return true
+}
+
+// correlate marks the spans of the transaction, in which the held errors occurred, as errored and
+// appends the spans of these errors to the transaction traces. The span ids of the transaction are kept
+// for the errors, which arrive later. It returns the number of correlated errors.
+func (ec *errorCorrelator) correlate(td ptrace.Traces, now time.Time) int {
+	spans := make(map[pcommon.SpanID]ptrace.Span)
+	traceIds := make(map[pcommon.TraceID]bool)
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		scopeSpans := td.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			spanSlice := scopeSpans.At(j).Spans()
+			for k := 0; k < spanSlice.Len(); k++ {
+				span := spanSlice.At(k)
+				spans[span.SpanID()] = span
+				traceIds[span.TraceID()] = true
+			}
+		}
+	}
+
+	ec.Lock()
+	for traceId := range traceIds {
+		recent, ok := ec.recent[traceId]
+		if !ok {
+			recent = &recentTransaction{spanIds: make(map[pcommon.SpanID]bool)}
+		}
+		recent.deadline = now.Add(ec.window)
+		for spanId, span := range spans {
+			if ec.recentCount >= ec.maxPending {
+				break
+			}
+			if span.TraceID() == traceId && !recent.spanIds[spanId] {
+				recent.spanIds[spanId] = true
+				ec.recentCount++
+			}
+		}
+		if len(recent.spanIds) > 0 {
+			ec.recent[traceId] = recent
+		}
+	}
+	correlated := make([]*heldError, 0)
+	for traceId := range traceIds {
+		remaining := make([]*heldError, 0, len(ec.pending[traceId]))
+		for _, held := range ec.pending[traceId] {
+			if _, ok := spans[held.spanId]; ok {
+				correlated = append(correlated, held)
+			} else {
+				remaining = append(remaining, held)
+			}
+		}
+		if len(remaining) == 0 {
+			delete(ec.pending, traceId)
+		} else {
+			ec.pending[traceId] = remaining
+		}
+	}
+	ec.count -= len(correlated)
+	ec.Unlock()
+
+	for _, held := range correlated {
+		markSpanErrored(spans[held.spanId], held)
+		held.traces.ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	}
+	return len(correlated)
+}
+
+// expired removes the errors, which waited longer than the window, or all errors, if all is true.
+// The outdated transactions are removed too.
+func (ec *errorCorrelator) expired(now time.Time, all bool) []*heldError {
+	ec.Lock()
+	defer ec.Unlock()
+	for traceId, recent := range ec.recent {
+		if !now.Before(recent.deadline) {
+			ec.recentCount -= len(recent.spanIds)
+			delete(ec.recent, traceId)
+		}
+	}
+	result := make([]*heldError, 0)
+	for traceId, heldErrors := range ec.pending {
+		remaining := make([]*heldError, 0, len(heldErrors))
+		for _, held := range heldErrors {
+			if all || !now.Before(held.deadline) {
+				result = append(result, held)
+			} else {
+				remaining = append(remaining, held)
+			}
+		}
+		if len(remaining) == 0 {
+			delete(ec.pending, traceId)
+		} else {
+			ec.pending[traceId] = remaining
+		}
+	}
+	ec.count -= len(result)
+	return result
+}
+
+func markSpanErrored(span ptrace.Span, held *heldError) {
+	setErrorStatus(span, held)
+	event := span.Events().AppendEmpty()
+	event.SetName(exceptionEventName)
+	event.SetTimestamp(held.timestamp)
+	putNotEmptyStr(event.Attributes(), conventions.AttributeExceptionType, held.exceptionType)
+	putNotEmptyStr(event.Attributes(), conventions.AttributeExceptionMessage, held.exceptionMessage)
+	putNotEmptyStr(event.Attributes(), "event_id", held.eventId)
+}
+
+func setErrorStatus(span ptrace.Span, held *heldError) {
+	span.Status().SetCode(ptrace.StatusCodeError)
+	switch {
+	case held.exceptionType != "" && held.exceptionMessage != "":
+		span.Status().SetMessage(held.exceptionType + ": " + held.exceptionMessage)
+	case held.exceptionType != "":
+		span.Status().SetMessage(held.exceptionType)
+	default:
+		span.Status().SetMessage(held.exceptionMessage)
+	}
+}
+
+func (sr *sentrytraceReceiver) isErrorEvent(event models.Event) bool {
+	level := sr.evaluateLevel(event)
+	return level == "error" || level == "fatal" || (level == "" && len(event.Exception.Values) > 0)
+}
+
+// correlateErrors holds the error event or attaches the held errors to the transaction.
+// It returns false, if the spans are held and must not be sent now, and the number of the held errors,
+// which are attached to the transaction.
+func (sr *sentrytraceReceiver) correlateErrors(ctx context.Context, envlp *models.EnvelopEventParseResult, td ptrace.Traces) (bool, int) {
+	if sr.errorCorrelator == nil {
+		return true, 0
+	}
+	now := time.Now()
+	if traceId, held := sr.newHeldError(envlp, td, now.Add(sr.errorCorrelator.window)); held != nil {
+		if sr.errorCorrelator.correlateLate(traceId, held, now) {
+			sr.telemetry.recordCorrelatedErrors(ctx, 1, true)
+			return true, 0
+		}
+		if sr.errorCorrelator.hold(traceId, held) {
+			return false, 0
+		}
+	}
+	correlated := 0
+	if envlp.EnvelopType == models.ENVELOP_TYPE_TRANSACTION {
+		if correlated = sr.errorCorrelator.correlate(td, now); correlated > 0 {
+			sr.telemetry.recordCorrelatedErrors(ctx, correlated, true)
+		}
+	}
+	return true, correlated
+}
+
+// recordLostErrors logs and counts the held error events, which can not be sent. Their envelopes are already
+// answered with success, so the SDK doesn't retry them and the held errors are delivered at most once.
+func (sr *sentrytraceReceiver) recordLostErrors(ctx context.Context, count int, err error) {
+	sr.logger.Sugar().Errorf("SentryReceiver : %v held error events are lost : %+v", count, err)
+	for i := 0; i < count; i++ {
+		sr.telemetry.recordSkippedItem(ctx, "event", skipReasonSendFailed)
+	}
+}
+
+func (sr *sentrytraceReceiver) flushHeldErrors(ctx context.Context, all bool) {
+	for _, held := range sr.errorCorrelator.expired(time.Now(), all) {
+		sr.telemetry.recordCorrelatedErrors(ctx, 1, false)
+		if err := sr.consumeTraces(ctx, held.traces); err != nil {
+			sr.recordLostErrors(ctx, 1, err)
+		}
+	}
+}
+
+func (sr *sentrytraceReceiver) expireHeldErrors(ctx context.Context) {
+	// the duration is checked by Config.Validate
+	window, _ := time.ParseDuration(sr.config.ErrorCorrelationCfg.Window)
+	ticker := time.NewTicker(window / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sr.flushHeldErrors(context.Background(), false)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+)
+
+// dedupEntryOverhead is the approximate size of the cache entry in bytes without the key:
+// the list element, the map bucket and the expiration time
+const dedupEntryOverhead = 128
+
+type dedupEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// dedupCache remembers the keys of the received envelopes for the time-to-live, so that the envelopes,
+// which are resent by the SDK retries and offline caches, are dropped. The keys are kept in the order
+// of their arrival, the oldest keys are evicted, when the cache exceeds the memory limit.
+type dedupCache struct {
+	sync.Mutex
+	ttl       time.Duration
+	maxMemory int
+	memory    int
+	entries   map[string]*list.Element
+	order     *list.List
+}
+
+func newDedupCache(ttl time.Duration, maxMemory int) *dedupCache {
+	return &dedupCache{
+		ttl:       ttl,
+		maxMemory: maxMemory,
+		entries:   make(map[string]*list.Element),
+		order:     list.New(),
+	}
+}
+
+// seen returns true, if the key is received within the time-to-live. Otherwise, the key is remembered.
+func (dc *dedupCache) seen(key string, now time.Time) bool {
+	dc.Lock()
+	defer dc.Unlock()
+	dc.evictExpired(now)
+	if _, ok := dc.entries[key]; ok {
+		return true
+	}
+	dc.entries[key] = dc.order.PushBack(&dedupEntry{key: key, expiresAt: now.Add(dc.ttl)})
+	dc.memory += len(key) + dedupEntryOverhead
+	for dc.memory > dc.maxMemory && dc.order.Len() > 0 {
+		dc.removeElement(dc.order.Front())
+	}
+	return false
+}
+
+// forget removes the key, so that the envelope is accepted again when the SDK retries it
+func (dc *dedupCache) forget(key string) {
+	dc.Lock()
+	defer dc.Unlock()
+	if element, ok := dc.entries[key]; ok {
+		dc.removeElement(element)
+	}
+}
+
+// evictExpired removes the expired keys. All keys have the same time-to-live, so the expired keys are at the front.
+func (dc *dedupCache) evictExpired(now time.Time) {
+	for element := dc.order.Front(); element != nil && !now.Before(element.Value.(*dedupEntry).expiresAt); element = dc.order.Front() {
+		dc.removeElement(element)
+	}
+}
+
+func (dc *dedupCache) removeElement(element *list.Element) {
+	entry := dc.order.Remove(element).(*dedupEntry)
+	delete(dc.entries, entry.key)
+	dc.memory -= len(entry.key) + dedupEntryOverhead
+}
+
+// getDedupKey returns the key of the envelope: the project and the event id for the events and transactions,
+// the project, the session id and the sequence number (or the timestamp, if seq is not set) for the sessions.
+// Empty key is returned for the other envelopes, they are not deduplicated.
+func getDedupKey(projectId string, envlp *models.EnvelopEventParseResult) string {
+	switch {
+	case len(envlp.Events) > 0:
+		eventId := envlp.Events[0].EventId
+		if eventId == "" {
+			eventId = envlp.EnvelopEventHeader.EventID
+		}
+		if eventId == "" {
+			return ""
+		}
+		return fmt.Sprintf("event|%v|%v", projectId, models.NormalizeEventId(eventId))
+	case len(envlp.SessionEvents) > 0:
+		session := envlp.SessionEvents[0]
+		if session.Sid == "" {
+			return ""
+		}
+		if session.Seq != 0 {
+			return fmt.Sprintf("session|%v|%v|%v", projectId, session.Sid, session.Seq)
+		}
+		if session.Timestamp.IsZero() {
+			return ""
+		}
+		return fmt.Sprintf("session|%v|%v|@%v", projectId, session.Sid, session.Timestamp)
+	}
+	return ""
+}
+
+// isDuplicate checks the envelope in the deduplication cache. It returns the key of the envelope,
+// which must be forgotten, if the envelope is not processed.
+func (sr *sentrytraceReceiver) isDuplicate(projectId string, envlp *models.EnvelopEventParseResult) (string, bool) {
+	if sr.dedupCache == nil {
+		return "", false
+	}
+	key := getDedupKey(projectId, envlp)
+	if key == "" {
+		return "", false
+	}
+	return key, sr.dedupCache.seen(key, time.Now())
+}
+
+// forgetDuplicate removes the key of the envelope, which is not processed because of the error
+func (sr *sentrytraceReceiver) forgetDuplicate(key string) {
+	if sr.dedupCache != nil && key != "" {
+		sr.dedupCache.forget(key)
+	}
+}
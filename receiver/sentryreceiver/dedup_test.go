@@ -0,0 +1,124 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+)
+
+func TestGetDedupKey(t *testing.T) {
+	timestamp := models.NewTimestamp(time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC))
+	tests := []struct {
+		name     string
+		envlp    models.EnvelopEventParseResult
+		expected string
+	}{
+		{
+			name:     "event id of the event",
+			envlp:    models.EnvelopEventParseResult{Events: []models.Event{{EventId: "0123456789abcdef0123456789abcdef"}}},
+			expected: "event|42|0123456789abcdef0123456789abcdef",
+		},
+		{
+			name:     "event id is normalized",
+			envlp:    models.EnvelopEventParseResult{Events: []models.Event{{EventId: "01234567-89AB-CDEF-0123-456789ABCDEF"}}},
+			expected: "event|42|0123456789abcdef0123456789abcdef",
+		},
+		{
+			name: "event id of the envelope header",
+			envlp: models.EnvelopEventParseResult{
+				EnvelopEventHeader: models.EnvelopEventHeader{EventID: "fedcba9876543210fedcba9876543210"},
+				Events:             []models.Event{{}},
+			},
+			expected: "event|42|fedcba9876543210fedcba9876543210",
+		},
+		{
+			name:     "event without event id",
+			envlp:    models.EnvelopEventParseResult{Events: []models.Event{{}}},
+			expected: "",
+		},
+		{
+			name:     "session with sequence number",
+			envlp:    models.EnvelopEventParseResult{SessionEvents: []models.SessionEvent{{Sid: "sid-1", Seq: 3, Timestamp: timestamp}}},
+			expected: "session|42|sid-1|3",
+		},
+		{
+			name:     "session with timestamp",
+			envlp:    models.EnvelopEventParseResult{SessionEvents: []models.SessionEvent{{Sid: "sid-1", Timestamp: timestamp}}},
+			expected: "session|42|sid-1|@2023-11-14T22:13:20Z",
+		},
+		{
+			name:     "session without sequence number and timestamp",
+			envlp:    models.EnvelopEventParseResult{SessionEvents: []models.SessionEvent{{Sid: "sid-1"}}},
+			expected: "",
+		},
+		{
+			name:     "session without sid",
+			envlp:    models.EnvelopEventParseResult{SessionEvents: []models.SessionEvent{{Seq: 3}}},
+			expected: "",
+		},
+		{
+			name:     "envelope without events and sessions",
+			envlp:    models.EnvelopEventParseResult{Metrics: []models.StatsdMetric{{Name: "a"}}},
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if key := getDedupKey("42", &tt.envlp); key != tt.expected {
+				t.Errorf("got %q, expected %q", key, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetDedupKeyIsPerProject(t *testing.T) {
+	envlp := &models.EnvelopEventParseResult{Events: []models.Event{{EventId: "0123456789abcdef0123456789abcdef"}}}
+	if getDedupKey("1", envlp) == getDedupKey("2", envlp) {
+		t.Error("the same event id of different projects must have different keys")
+	}
+}
+
+func TestDedupCache(t *testing.T) {
+	now := time.Now()
+	cache := newDedupCache(time.Minute, 10*(dedupEntryOverhead+1))
+
+	if cache.seen("a", now) {
+		t.Error("the first envelope must not be a duplicate")
+	}
+	if !cache.seen("a", now.Add(30*time.Second)) {
+		t.Error("the envelope within the time-to-live must be a duplicate")
+	}
+	if cache.seen("a", now.Add(time.Minute)) {
+		t.Error("the envelope after the time-to-live must not be a duplicate")
+	}
+
+	cache.forget("a")
+	if cache.seen("a", now.Add(time.Minute)) {
+		t.Error("the forgotten envelope must not be a duplicate")
+	}
+
+	for _, key := range []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "b"} {
+		cache.seen(key, now.Add(time.Minute))
+	}
+	if len(cache.entries) != 10 || cache.memory > cache.maxMemory {
+		t.Errorf("the cache must be limited by the memory, got %v entries and %v bytes", len(cache.entries), cache.memory)
+	}
+	if cache.seen("a", now.Add(time.Minute)) {
+		t.Error("the oldest key must be evicted, when the memory limit is exceeded")
+	}
+}
@@ -15,45 +15,185 @@
 package sentryreceiver
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
 )
 
-func (sr *sentrytraceReceiver) ParseEnvelopEvent(body string) (*models.EnvelopEventParseResult, error) {
+// Reasons of the envelope parse failures
+const (
+	parseFailureTooFewLines       = "too_few_lines"
+	parseFailureInvalidHeader     = "invalid_header"
+	parseFailureInvalidItemHeader = "invalid_item_header"
+	parseFailureInvalidItemLength = "invalid_item_length"
+	parseFailureInvalidPayload    = "invalid_payload"
+	parseFailureNoUsefulPayload   = "no_useful_payload"
+)
+
+// envelopParseError keeps the reason of the parse failure and the type of the item, which can not be parsed
+type envelopParseError struct {
+	reason   string
+	itemType string
+	err      error
+}
+
+func (e *envelopParseError) Error() string {
+	return e.err.Error()
+}
+
+func (e *envelopParseError) Unwrap() error {
+	return e.err
+}
+
+func newEnvelopParseError(reason string, itemType string, err error) error {
+	return &envelopParseError{reason: reason, itemType: itemType, err: err}
+}
+
+func (sr *sentrytraceReceiver) recordParseFailure(ctx context.Context, err error) {
+	var parseErr *envelopParseError
+	if errors.As(err, &parseErr) {
+		sr.telemetry.recordParseFailure(ctx, parseErr.reason, parseErr.itemType)
+	} else {
+		sr.telemetry.recordParseFailure(ctx, "unknown", "")
+	}
+}
+
+// itemError handles the error of the envelope item. In strict mode the error is returned and the whole envelope is rejected.
+// Otherwise the item is skipped, so that the valid items of the envelope are processed.
+func (sr *sentrytraceReceiver) itemError(ctx context.Context, itemErrors *[]error, reason string, itemType string, err error) error {
+	parseErr := newEnvelopParseError(reason, itemType, err)
+	if sr.config.StrictParsing {
+		return parseErr
+	}
+	sr.logger.Sugar().Warnf("SentryReceiver : %v item is skipped : %+v", itemType, err)
+	sr.telemetry.recordSkippedItem(ctx, itemType, reason)
+	*itemErrors = append(*itemErrors, parseErr)
+	return nil
+}
+
+func (sr *sentrytraceReceiver) ParseEnvelopEvent(ctx context.Context, body string) (*models.EnvelopEventParseResult, error) {
 	logger := sr.logger
 	logger.Sugar().Debugf("SentryReceiver : Start parsing envelop :\n---START---\n%+v\n---END---\n", body)
-	lines := strings.Split(body, "\n")
+	reader := newEnvelopReader(body)
 
 	var header models.EnvelopEventHeader
 	var type_header models.EnvelopTypeHeader
 	events := make([]models.Event, 0)
 	sessionEvents := make([]models.SessionEvent, 0)
-	linesCount := len(lines)
+	metrics := make([]models.StatsdMetric, 0)
+	spans := make([]models.EventSpan, 0)
+	feedbacks := make([]models.UserFeedback, 0)
+	attachments := make([]models.Attachment, 0)
+	itemErrors := make([]error, 0)
+	linesCount := strings.Count(body, "\n") + 1
 	if linesCount < 3 {
-		return nil, fmt.Errorf("Unexpected number of lines in the envelope : %v. Must be 3 or greater", linesCount)
+		return nil, newEnvelopParseError(parseFailureTooFewLines, "", fmt.Errorf("Unexpected number of lines in the envelope : %v. Must be 3 or greater", linesCount))
 	}
 
-	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+	if err := json.Unmarshal([]byte(reader.readLine()), &header); err != nil {
 		logger.Sugar().Errorf("Unmarshal header error: %+v", err.Error())
-		return nil, err
+		return nil, newEnvelopParseError(parseFailureInvalidHeader, "", err)
 	}
 
 	var envelopType = models.ENVELOP_TYPE_UNKNOWN
-	for i := 1; i+1 < linesCount; i += 2 {
-		header := lines[i]
-		if len(header) < 2 {
+	for !reader.eof() {
+		itemHeader := reader.readLine()
+		if len(itemHeader) < 2 {
 			continue
 		}
-		payload := lines[i+1]
+		type_header = models.EnvelopTypeHeader{}
+		if err := json.Unmarshal([]byte(itemHeader), &type_header); err != nil {
+			logger.Sugar().Errorf("Unmarshal type_header error: %+v", err.Error())
+			// the rest of the envelope can not be read without the item length
+			if err := sr.itemError(ctx, &itemErrors, parseFailureInvalidItemHeader, "", err); err != nil {
+				return nil, err
+			}
+			break
+		}
+		sr.telemetry.recordItem(ctx, type_header.Type)
+		payload, err := reader.readPayload(type_header.Length)
+		if err != nil {
+			logger.Sugar().Errorf("SentryReceiver : Error reading %v item payload: %+v", type_header.Type, err)
+			if err := sr.itemError(ctx, &itemErrors, parseFailureInvalidItemLength, type_header.Type, err); err != nil {
+				return nil, err
+			}
+			break
+		}
 		if len(payload) < 2 {
+			sr.telemetry.recordSkippedItem(ctx, type_header.Type, skipReasonEmpty)
 			continue
 		}
-		if err := json.Unmarshal([]byte(header), &type_header); err != nil {
-			logger.Sugar().Errorf("Unmarshal type_header error: %+v", err.Error())
-			return nil, err
+
+		if type_header.Type == "statsd" {
+			itemMetrics, err := models.ParseStatsdPayload(payload)
+			if err != nil {
+				logger.Sugar().Errorf("SentryReceiver : Parse statsd item error: %+v ; Payload: %+v", err.Error(), payload)
+				if err := sr.itemError(ctx, &itemErrors, parseFailureInvalidPayload, type_header.Type, err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			metrics = append(metrics, itemMetrics...)
+			continue
+		}
+
+		if type_header.Type == "span" {
+			var span models.EventSpan
+			if err := json.Unmarshal([]byte(payload), &span); err != nil {
+				logger.Sugar().Errorf("SentryReceiver : Unmarshal span error: %+v ; Payload: %+v", err.Error(), payload)
+				if err := sr.itemError(ctx, &itemErrors, parseFailureInvalidPayload, type_header.Type, err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			spans = append(spans, span)
+			continue
+		}
+
+		if type_header.Type == "feedback" {
+			var event models.Event
+			if err := json.Unmarshal([]byte(payload), &event); err != nil {
+				logger.Sugar().Errorf("SentryReceiver : Unmarshal feedback error: %+v ; Payload: %+v", err.Error(), payload)
+				if err := sr.itemError(ctx, &itemErrors, parseFailureInvalidPayload, type_header.Type, err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			feedbacks = append(feedbacks, models.NewUserFeedbackFromEvent(&event))
+			continue
+		}
+
+		if type_header.Type == "attachment" {
+			attachments = append(attachments, models.Attachment{
+				Filename:       type_header.Filename,
+				ContentType:    type_header.ContentType,
+				AttachmentType: type_header.AttachmentType,
+				Data:           []byte(payload),
+			})
+			continue
+		}
+
+		if type_header.Type == "user_report" {
+			var report models.UserReport
+			if err := json.Unmarshal([]byte(payload), &report); err != nil {
+				logger.Sugar().Errorf("SentryReceiver : Unmarshal user_report error: %+v ; Payload: %+v", err.Error(), payload)
+				if err := sr.itemError(ctx, &itemErrors, parseFailureInvalidPayload, type_header.Type, err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			feedbacks = append(feedbacks, models.NewUserFeedbackFromUserReport(&report))
+			continue
+		}
+
+		// Only the first event, transaction or session item of the envelope is processed
+		if envelopType != models.ENVELOP_TYPE_UNKNOWN {
+			sr.telemetry.recordSkippedItem(ctx, type_header.Type, skipReasonDuplicate)
+			continue
 		}
 		switch type_header.Type {
 		case "transaction":
@@ -64,6 +204,7 @@ func (sr *sentrytraceReceiver) ParseEnvelopEvent(body string) (*models.EnvelopEv
 			envelopType = models.ENVELOP_TYPE_SESSION
 		default:
 			logger.Sugar().Infof("Received %v item header. Skipping this item", type_header.Type)
+			sr.telemetry.recordSkippedItem(ctx, type_header.Type, skipReasonUnsupported)
 			continue
 		}
 
@@ -71,22 +212,32 @@ func (sr *sentrytraceReceiver) ParseEnvelopEvent(body string) (*models.EnvelopEv
 			var sessionEvent models.SessionEvent
 			if err := json.Unmarshal([]byte(payload), &sessionEvent); err != nil {
 				logger.Sugar().Errorf("SentryReceiver : Unmarshal session event error: %+v ; Payload: %+v", err.Error(), payload)
-				return nil, err
+				if err := sr.itemError(ctx, &itemErrors, parseFailureInvalidPayload, type_header.Type, err); err != nil {
+					return nil, err
+				}
+				envelopType = models.ENVELOP_TYPE_UNKNOWN
+				continue
 			}
 			sessionEvents = append(sessionEvents, sessionEvent)
 		} else {
 			var event models.Event
 			if err := json.Unmarshal([]byte(payload), &event); err != nil {
 				logger.Sugar().Errorf("SentryReceiver : Unmarshal event error: %+v ; Payload: %+v", err.Error(), payload)
-				return nil, err
+				if err := sr.itemError(ctx, &itemErrors, parseFailureInvalidPayload, type_header.Type, err); err != nil {
+					return nil, err
+				}
+				envelopType = models.ENVELOP_TYPE_UNKNOWN
+				continue
 			}
 			events = append(events, event)
 		}
-		break
 	}
 
-	if len(events) == 0 && len(sessionEvents) == 0 {
-		return nil, fmt.Errorf("No useful payload in the envelop")
+	if len(events) == 0 && len(sessionEvents) == 0 && len(metrics) == 0 && len(spans) == 0 && len(feedbacks) == 0 && len(attachments) == 0 {
+		if len(itemErrors) > 0 {
+			return nil, itemErrors[0]
+		}
+		return nil, newEnvelopParseError(parseFailureNoUsefulPayload, "", fmt.Errorf("No useful payload in the envelop"))
 	}
 
 	result := models.EnvelopEventParseResult{
@@ -94,7 +245,56 @@ func (sr *sentrytraceReceiver) ParseEnvelopEvent(body string) (*models.EnvelopEv
 		EnvelopEventHeader: header,
 		Events:             events,
 		SessionEvents:      sessionEvents,
+		Metrics:            metrics,
+		Spans:              spans,
+		Feedbacks:          feedbacks,
+		Attachments:        attachments,
 		EnvelopType:        envelopType,
+		ItemErrors:         itemErrors,
 	}
 	return &result, nil
 }
+
+// envelopReader reads the envelope items. The payload of an item is either
+// the number of bytes from the "length" item header or the rest of the line.
+type envelopReader struct {
+	body string
+	pos  int
+}
+
+func newEnvelopReader(body string) *envelopReader {
+	return &envelopReader{body: body}
+}
+
+func (er *envelopReader) eof() bool {
+	return er.pos >= len(er.body)
+}
+
+func (er *envelopReader) readLine() string {
+	if er.eof() {
+		return ""
+	}
+	rest := er.body[er.pos:]
+	idx := strings.IndexByte(rest, '\n')
+	if idx < 0 {
+		er.pos = len(er.body)
+		return rest
+	}
+	er.pos += idx + 1
+	return rest[:idx]
+}
+
+func (er *envelopReader) readPayload(length int) (string, error) {
+	if length <= 0 {
+		return er.readLine(), nil
+	}
+	if er.pos+length > len(er.body) {
+		return "", fmt.Errorf("item length %v exceeds the envelope size", length)
+	}
+	payload := er.body[er.pos : er.pos+length]
+	er.pos += length
+	if !er.eof() && er.body[er.pos] == '\n' {
+		er.pos++
+	}
+	return payload, nil
+}
@@ -0,0 +1,220 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/receiver"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+)
+
+// newTestReceiver creates the receiver with the no-op telemetry. The receiver is not started.
+func newTestReceiver(t *testing.T, config *Config) *sentrytraceReceiver {
+	t.Helper()
+	settings := receiver.Settings{
+		ID: component.NewID(component.MustNewType(typeStr)),
+		TelemetrySettings: component.TelemetrySettings{
+			Logger:         zap.NewNop(),
+			MeterProvider:  metricnoop.NewMeterProvider(),
+			TracerProvider: tracenoop.NewTracerProvider(),
+		},
+	}
+	sr, err := newReceiver(config, settings)
+	if err != nil {
+		t.Fatalf("can not create the receiver : %v", err)
+	}
+	return sr
+}
+
+const (
+	testEnvelopeHeader = `{"event_id":"0123456789abcdef0123456789abcdef","sent_at":"2023-11-14T22:13:20Z"}`
+	testEventPayload   = `{"event_id":"0123456789abcdef0123456789abcdef","message":"boom"}`
+)
+
+func TestParseEnvelopEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		// lenient parsing
+		envelopType int
+		events      int
+		sessions    int
+		metrics     int
+		spans       int
+		feedbacks   int
+		attachments int
+		itemErrors  int
+		wantErr     string
+		// strict parsing, the same result is expected, if strictErr is empty
+		strictErr string
+	}{
+		{
+			name:        "event",
+			body:        testEnvelopeHeader + "\n" + `{"type":"event"}` + "\n" + testEventPayload + "\n",
+			envelopType: models.ENVELOP_TYPE_EVENT,
+			events:      1,
+		},
+		{
+			name:        "event with item length and new lines in the payload",
+			body:        testEnvelopeHeader + "\n" + `{"type":"event","length":23}` + "\n" + "{\"message\":\n\"boom\"    }\n",
+			envelopType: models.ENVELOP_TYPE_EVENT,
+			events:      1,
+		},
+		{
+			name:        "transaction and standalone span",
+			body:        "{}\n" + `{"type":"transaction"}` + "\n" + `{"transaction":"/home"}` + "\n" + `{"type":"span"}` + "\n" + `{"span_id":"0123456789abcdef"}` + "\n",
+			envelopType: models.ENVELOP_TYPE_TRANSACTION,
+			events:      1,
+			spans:       1,
+		},
+		{
+			name:        "session",
+			body:        "{}\n" + `{"type":"session"}` + "\n" + `{"sid":"7c7b6585-f901-4351-bf8d-2f1d0c4b7f8e","status":"ok"}` + "\n",
+			envelopType: models.ENVELOP_TYPE_SESSION,
+			sessions:    1,
+		},
+		{
+			name:    "statsd, feedback, user report and attachment",
+			body:    "{}\n" + `{"type":"statsd"}` + "\n" + "a:1|c\n" + `{"type":"feedback"}` + "\n" + `{"contexts":{"feedback":{"message":"nice"}}}` + "\n" + `{"type":"user_report"}` + "\n" + `{"event_id":"0123456789abcdef0123456789abcdef","comments":"bad"}` + "\n" + `{"type":"attachment","length":5,"filename":"a.txt"}` + "\n" + "hello\n",
+			metrics: 1, feedbacks: 2, attachments: 1,
+		},
+		{
+			name:        "only the first event is processed",
+			body:        "{}\n" + `{"type":"event"}` + "\n" + testEventPayload + "\n" + `{"type":"transaction"}` + "\n" + `{"transaction":"/home"}` + "\n",
+			envelopType: models.ENVELOP_TYPE_EVENT,
+			events:      1,
+		},
+		{
+			name:        "unsupported and empty items are skipped",
+			body:        "{}\n" + `{"type":"client_report"}` + "\n" + `{"discarded_events":[]}` + "\n" + `{"type":"span"}` + "\n" + "\n" + `{"type":"event"}` + "\n" + testEventPayload + "\n",
+			envelopType: models.ENVELOP_TYPE_EVENT,
+			events:      1,
+		},
+		{
+			name:        "invalid event payload is skipped and the next event is processed",
+			body:        "{}\n" + `{"type":"event"}` + "\n" + `{"message":` + "\n" + `{"type":"event"}` + "\n" + testEventPayload + "\n",
+			envelopType: models.ENVELOP_TYPE_EVENT,
+			events:      1,
+			itemErrors:  1,
+			strictErr:   parseFailureInvalidPayload,
+		},
+		{
+			name:       "invalid statsd item is skipped",
+			body:       "{}\n" + `{"type":"statsd"}` + "\n" + "a:x|c\n" + `{"type":"span"}` + "\n" + `{"span_id":"0123456789abcdef"}` + "\n",
+			spans:      1,
+			itemErrors: 1,
+			strictErr:  parseFailureInvalidPayload,
+		},
+		{
+			name:        "invalid item header stops the reading",
+			body:        "{}\n" + `{"type":"event"}` + "\n" + testEventPayload + "\n" + `{"type":` + "\n" + `{"type":"span"}` + "\n" + `{"span_id":"0123456789abcdef"}` + "\n",
+			envelopType: models.ENVELOP_TYPE_EVENT,
+			events:      1,
+			itemErrors:  1,
+			strictErr:   parseFailureInvalidItemHeader,
+		},
+		{
+			name:        "item length exceeding the envelope stops the reading",
+			body:        "{}\n" + `{"type":"event"}` + "\n" + testEventPayload + "\n" + `{"type":"attachment","length":1000}` + "\n" + "hello\n",
+			envelopType: models.ENVELOP_TYPE_EVENT,
+			events:      1,
+			itemErrors:  1,
+			strictErr:   parseFailureInvalidItemLength,
+		},
+		{
+			name:    "all items are invalid",
+			body:    "{}\n" + `{"type":"event"}` + "\n" + `{"message":` + "\n",
+			wantErr: parseFailureInvalidPayload,
+		},
+		{
+			name:    "too few lines",
+			body:    testEnvelopeHeader + "\n" + `{"type":"event"}`,
+			wantErr: parseFailureTooFewLines,
+		},
+		{
+			name:    "invalid envelope header",
+			body:    `{"event_id":` + "\n" + `{"type":"event"}` + "\n" + testEventPayload + "\n",
+			wantErr: parseFailureInvalidHeader,
+		},
+		{
+			name:    "no useful payload",
+			body:    "{}\n" + `{"type":"client_report"}` + "\n" + `{"discarded_events":[]}` + "\n",
+			wantErr: parseFailureNoUsefulPayload,
+		},
+	}
+	for _, tt := range tests {
+		for _, strict := range []bool{false, true} {
+			name := tt.name + " (lenient)"
+			if strict {
+				name = tt.name + " (strict)"
+			}
+			t.Run(name, func(t *testing.T) {
+				config := createDefaultConfig().(*Config)
+				config.StrictParsing = strict
+				sr := newTestReceiver(t, config)
+
+				envlp, err := sr.ParseEnvelopEvent(context.Background(), tt.body)
+				wantErr := tt.wantErr
+				if strict && tt.strictErr != "" {
+					wantErr = tt.strictErr
+				}
+				if wantErr != "" {
+					var parseErr *envelopParseError
+					if !errors.As(err, &parseErr) || parseErr.reason != wantErr {
+						t.Fatalf("expected %v parse error, got %v", wantErr, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("unexpected error : %v", err)
+				}
+				if envlp.EnvelopType != tt.envelopType || len(envlp.Events) != tt.events || len(envlp.SessionEvents) != tt.sessions ||
+					len(envlp.Metrics) != tt.metrics || len(envlp.Spans) != tt.spans || len(envlp.Feedbacks) != tt.feedbacks ||
+					len(envlp.Attachments) != tt.attachments || len(envlp.ItemErrors) != tt.itemErrors {
+					t.Errorf("got type %v, %v events, %v sessions, %v metrics, %v spans, %v feedbacks, %v attachments, %v item errors",
+						envlp.EnvelopType, len(envlp.Events), len(envlp.SessionEvents), len(envlp.Metrics), len(envlp.Spans),
+						len(envlp.Feedbacks), len(envlp.Attachments), len(envlp.ItemErrors))
+				}
+			})
+		}
+	}
+}
+
+func TestParseEnvelopEventPayload(t *testing.T) {
+	sr := newTestReceiver(t, createDefaultConfig().(*Config))
+	body := testEnvelopeHeader + "\n" + `{"type":"event","length":23}` + "\n" + "{\"message\":\n\"boom\"    }\n" +
+		`{"type":"attachment","length":6,"filename":"a.txt","content_type":"text/plain"}` + "\n" + "he\nllo\n"
+	envlp, err := sr.ParseEnvelopEvent(context.Background(), body)
+	if err != nil {
+		t.Fatalf("unexpected error : %v", err)
+	}
+	if envlp.EnvelopEventHeader.EventID != "0123456789abcdef0123456789abcdef" || envlp.EnvelopEventHeader.SentAt != "2023-11-14T22:13:20Z" {
+		t.Errorf("unexpected envelope header %+v", envlp.EnvelopEventHeader)
+	}
+	if envlp.Events[0].Message != "boom" {
+		t.Errorf("message = %q, expected boom", envlp.Events[0].Message)
+	}
+	attachment := envlp.Attachments[0]
+	if string(attachment.Data) != "he\nllo" || attachment.Filename != "a.txt" || attachment.ContentType != "text/plain" {
+		t.Errorf("unexpected attachment %+v", attachment)
+	}
+}
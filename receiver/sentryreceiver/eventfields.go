@@ -0,0 +1,172 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const threadEventName = "thread"
+
+// putEventFields adds extra, modules, threads, fingerprint, server_name and debug_meta of the event to the span
+// according to event-fields settings
+func (sr *sentrytraceReceiver) putEventFields(span ptrace.Span, event models.Event) {
+	cfg := sr.config.EventFieldsCfg
+	attrs := span.Attributes()
+	putNotEmptyStr(attrs, "server_name", event.ServerName)
+	if len(event.Fingerprint) > 0 {
+		fingerprint := attrs.PutEmptySlice("fingerprint")
+		for _, value := range event.Fingerprint {
+			fingerprint.AppendEmpty().SetStr(value)
+		}
+	}
+
+	if cfg.Extra && len(event.Extra) > 0 {
+		keys := sr.limitKeys(attrs, "extra", sortedKeys(event.Extra))
+		for _, key := range keys {
+			sr.extraFlattener.put(attrs, "extra."+key, truncateStrings(event.Extra[key], cfg.MaxValueLength))
+		}
+	}
+
+	if cfg.Modules && len(event.Modules) > 0 {
+		keys := sr.limitKeys(attrs, "modules", sortedKeys(event.Modules))
+		for _, key := range keys {
+			attrs.PutStr("modules."+key, truncateString(event.Modules[key], cfg.MaxValueLength))
+		}
+	}
+
+	if cfg.Threads && len(event.Threads) > 0 {
+		threads := event.Threads
+		if cfg.MaxItems > 0 && len(threads) > cfg.MaxItems {
+			attrs.PutInt("threads.dropped", int64(len(threads)-cfg.MaxItems))
+			threads = threads[:cfg.MaxItems]
+		}
+		for _, thread := range threads {
+			spanEvent := span.Events().AppendEmpty()
+			spanEvent.SetName(threadEventName)
+			spanEvent.SetTimestamp(span.EndTimestamp())
+			eventAttrs := spanEvent.Attributes()
+			if thread.Id != nil {
+				eventAttrs.PutStr("thread.id", fmt.Sprintf("%v", thread.Id))
+			}
+			putNotEmptyStr(eventAttrs, "thread.name", thread.Name)
+			putNotEmptyStr(eventAttrs, "thread.state", thread.State)
+			eventAttrs.PutBool("thread.crashed", thread.Crashed)
+			eventAttrs.PutBool("thread.current", thread.Current)
+			eventAttrs.PutBool("thread.main", thread.Main)
+			if thread.Stacktrace != nil {
+				putNotEmptyStr(eventAttrs, "thread.stacktrace", truncateString(formatStacktrace(*thread.Stacktrace), cfg.MaxValueLength))
+			}
+		}
+	}
+
+	if cfg.DebugMeta {
+		images := event.DebugMeta.Images
+		if cfg.MaxItems > 0 && len(images) > cfg.MaxItems {
+			attrs.PutInt("debug_meta.images.dropped", int64(len(images)-cfg.MaxItems))
+			images = images[:cfg.MaxItems]
+		}
+		if len(images) > 0 {
+			imagesSlice := attrs.PutEmptySlice("debug_meta.images")
+			for _, image := range images {
+				setAttributeValue(imagesSlice.AppendEmpty(), truncateStrings(image, cfg.MaxValueLength))
+			}
+		}
+		if len(event.DebugMeta.SdkInfo) > 0 {
+			putFlattenedAttributes(attrs, "debug_meta.sdk_info", truncateStrings(event.DebugMeta.SdkInfo, cfg.MaxValueLength))
+		}
+	}
+}
+
+// limitKeys returns at most event-fields.max-items keys (0 means no limit) and puts the number of the dropped keys to <prefix>.dropped attribute
+func (sr *sentrytraceReceiver) limitKeys(attrs pcommon.Map, prefix string, keys []string) []string {
+	maxItems := sr.config.EventFieldsCfg.MaxItems
+	if maxItems == 0 || len(keys) <= maxItems {
+		return keys
+	}
+	attrs.PutInt(prefix+".dropped", int64(len(keys)-maxItems))
+	return keys[:maxItems]
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatStacktrace formats the frames from the most recent one to the oldest one, e.g. "handle (app/views.py:42)"
+func formatStacktrace(stacktrace models.Stacktrace) string {
+	var result strings.Builder
+	for i := len(stacktrace.Frames) - 1; i >= 0; i-- {
+		frame := stacktrace.Frames[i]
+		function := frame.Function
+		if function == "" {
+			function = "?"
+		}
+		location := frame.Filename
+		if location == "" {
+			location = frame.Module
+		}
+		if frame.Lineno > 0 {
+			location = fmt.Sprintf("%v:%v", location, frame.Lineno)
+		}
+		if result.Len() > 0 {
+			result.WriteByte('\n')
+		}
+		result.WriteString(function + " (" + location + ")")
+	}
+	return result.String()
+}
+
+// truncateString cuts the string to maxLength bytes. 0 means no limit.
+func truncateString(value string, maxLength int) string {
+	if maxLength <= 0 || len(value) <= maxLength {
+		return value
+	}
+	return strings.ToValidUTF8(value[:maxLength], "") + "..."
+}
+
+// truncateStrings cuts all strings of the JSON value
+func truncateStrings(value interface{}, maxLength int) interface{} {
+	if maxLength <= 0 {
+		return value
+	}
+	switch valTyped := value.(type) {
+	case string:
+		return truncateString(valTyped, maxLength)
+	case []interface{}:
+		result := make([]interface{}, len(valTyped))
+		for i, item := range valTyped {
+			result[i] = truncateStrings(item, maxLength)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(valTyped))
+		for k, v := range valTyped {
+			result[k] = truncateStrings(v, maxLength)
+		}
+		return result
+	}
+	return value
+}
@@ -17,6 +17,7 @@ package sentryreceiver
 import (
 	"context"
 	"errors"
+	"sync"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
@@ -34,7 +35,8 @@ func NewFactory() receiver.Factory {
 	return receiver.NewFactory(
 		component.MustNewType(typeStr),
 		createDefaultConfig,
-		receiver.WithTraces(createTracesReceiver, component.StabilityLevelAlpha))
+		receiver.WithTraces(createTracesReceiver, component.StabilityLevelAlpha),
+		receiver.WithMetrics(createMetricsReceiver, component.StabilityLevelDevelopment))
 }
 
 func createDefaultConfig() component.Config {
@@ -42,6 +44,63 @@ func createDefaultConfig() component.Config {
 		ServerConfig: confighttp.ServerConfig{
 			Endpoint: defaultBindEndpoint,
 		},
+		StatsdMetricsCfg: StatsdMetricsConfig{
+			DistributionBuckets: []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000},
+		},
+		BreadcrumbsCfg: BreadcrumbsConfig{
+			MaxCount: 100,
+		},
+		ContextsCfg: ContextsConfig{
+			MaxDepth: 3,
+		},
+		EventFieldsCfg: EventFieldsConfig{
+			Extra:          true,
+			Modules:        true,
+			Threads:        true,
+			DebugMeta:      true,
+			MaxDepth:       3,
+			MaxItems:       100,
+			MaxValueLength: 4096,
+		},
+		ScrubbingCfg: ScrubbingConfig{
+			Replacement: "[Filtered]",
+			MaskEmails:  true,
+			SensitiveKeys: []string{"password", "passwd", "secret", "token", "api_key", "apikey", "auth", "credentials",
+				"session", "csrf", "xsrf", "cookie", "private_key"},
+		},
+		RequestCfg: RequestConfig{
+			MaxValueLength: 1024,
+		},
+		AttachmentsCfg: AttachmentsConfig{
+			MaxAttachmentSize:       10 * 1024 * 1024,
+			MaxEventAttachmentsSize: 20 * 1024 * 1024,
+			MaxTotalSize:            1024 * 1024 * 1024,
+			Retention:               "168h",
+			CleanupInterval:         "1h",
+		},
+		ProjectsCfg: ProjectsConfig{
+			ReloadInterval: "30s",
+		},
+		ErrorCorrelationCfg: ErrorCorrelationConfig{
+			Window:           "10s",
+			MaxPendingEvents: 10000,
+		},
+		SessionsCfg: SessionsConfig{
+			Timeout:     "30m",
+			MaxSessions: 100000,
+		},
+		DeduplicationCfg: DeduplicationConfig{
+			Ttl:       "10m",
+			MaxMemory: 16 * 1024 * 1024,
+		},
+		ClockSkewCfg: ClockSkewConfig{
+			Threshold: "1m",
+		},
+		FingerprintingCfg: FingerprintingConfig{
+			Enabled:              true,
+			Strategy:             "v1",
+			UseClientFingerprint: true,
+		},
 	}
 }
 
@@ -49,5 +108,49 @@ func createTracesReceiver(_ context.Context, params receiver.Settings, baseCfg c
 	if consumer == nil {
 		return nil, errors.New("nil next Consumer")
 	}
-	return newReceiver(baseCfg.(*Config), consumer, params)
+	sr, err := getOrCreateReceiver(baseCfg.(*Config), params)
+	if err != nil {
+		return nil, err
+	}
+	sr.nextConsumer = consumer
+	return sr, nil
+}
+
+func createMetricsReceiver(_ context.Context, params receiver.Settings, baseCfg component.Config, consumer consumer.Metrics) (receiver.Metrics, error) {
+	if consumer == nil {
+		return nil, errors.New("nil next Consumer")
+	}
+	sr, err := getOrCreateReceiver(baseCfg.(*Config), params)
+	if err != nil {
+		return nil, err
+	}
+	sr.metricsConsumer = consumer
+	return sr, nil
+}
+
+// The traces and metrics receivers with the same config share one instance,
+// so that both signals are served by a single http endpoint.
+var (
+	receiversMu sync.Mutex
+	receivers   = map[*Config]*sentrytraceReceiver{}
+)
+
+func getOrCreateReceiver(cfg *Config, params receiver.Settings) (*sentrytraceReceiver, error) {
+	receiversMu.Lock()
+	defer receiversMu.Unlock()
+	if sr, ok := receivers[cfg]; ok {
+		return sr, nil
+	}
+	sr, err := newReceiver(cfg, params)
+	if err != nil {
+		return nil, err
+	}
+	receivers[cfg] = sr
+	return sr, nil
+}
+
+func removeReceiver(cfg *Config) {
+	receiversMu.Lock()
+	defer receiversMu.Unlock()
+	delete(receivers, cfg)
 }
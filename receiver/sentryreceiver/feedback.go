@@ -0,0 +1,120 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	feedbackSpanName = "Feedback"
+	feedbackCategory = "user-feedback"
+)
+
+// appendScopeSpansForFeedbacks converts the user feedback to the spans with name "Feedback".
+// The span belongs to the trace of the feedback event or, for legacy user_report items, to the trace
+// which id is the id of the event the feedback is given for.
+func (sr *sentrytraceReceiver) appendScopeSpansForFeedbacks(rsb *resourceSpansBuilder, envlp *models.EnvelopEventParseResult, r *http.Request) {
+	for _, feedback := range envlp.Feedbacks {
+		var scopeSpans ptrace.ScopeSpans
+		if feedback.Event != nil {
+			scopeSpans = rsb.scopeSpans(feedback.Event.Release, feedback.Event.Environment, feedback.Event.Platform)
+		} else {
+			scopeSpans = rsb.scopeSpans("", "", "")
+		}
+		span := scopeSpans.Spans().AppendEmpty()
+		span.SetName(feedbackSpanName)
+		span.SetKind(ptrace.SpanKindClient)
+		attrs := span.Attributes()
+
+		timestamp := time.Now()
+		if event := feedback.Event; event != nil {
+			if event.Contexts.Trace.TraceID != "" {
+				span.SetTraceID(sr.GenerateTraceID(event.Contexts.Trace.TraceID))
+				span.SetParentSpanID(sr.GenerateSpanId(event.Contexts.Trace.SpanID))
+			} else {
+				span.SetTraceID(sr.GenerateTraceID(event.EventId))
+			}
+			if len(event.EventId) >= 16 {
+				span.SetSpanID(sr.GenerateSpanId(event.EventId[0:16]))
+			}
+			if !event.Timestamp.IsZero() {
+				timestamp = event.Timestamp.Time()
+			}
+			if event.EventId != "" {
+				attrs.PutStr("event_id", event.EventId)
+			}
+			if event.Release != "" {
+				attrs.PutStr("version", event.Release)
+			}
+			if event.Environment != "" {
+				attrs.PutStr("environment", event.Environment)
+			}
+			if event.Platform != "" {
+				attrs.PutStr("platform", event.Platform)
+			}
+			sdk := event.Sdk.Name + "@" + event.Sdk.Version
+			if sdk != "@" {
+				attrs.PutStr("sdk", sdk)
+			}
+			if event.User.Id != "" {
+				attrs.PutStr("user_id", string(event.User.Id))
+			}
+			if feedback.Url == "" {
+				feedback.Url = event.Request.URL
+			}
+			sr.putAttachmentsAttribute(attrs, event.EventId, envlp)
+		} else {
+			span.SetTraceID(sr.GenerateTraceID(removeHyphens(feedback.AssociatedEventId)))
+			if eventId := removeHyphens(feedback.AssociatedEventId); len(eventId) == 32 {
+				span.SetSpanID(sr.GenerateSpanId(eventId[16:32]))
+				span.SetParentSpanID(sr.GenerateSpanId(eventId[0:16]))
+			}
+		}
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(timestamp))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(timestamp))
+
+		attrs.PutInt("sentry.envelop.type.int", models.ENVELOP_TYPE_FEEDBACK)
+		attrs.PutStr("sentry.envelop.type", "feedback")
+		attrs.PutStr("category", feedbackCategory)
+		name := sr.GetServiceName(r)
+		if name != "" {
+			attrs.PutStr("name", name)
+		}
+		serviceName := r.Header.Get("x-service-name")
+		if serviceName != "" {
+			attrs.PutStr("service.name", serviceName)
+		}
+
+		putNotEmptyStr(attrs, "feedback.name", feedback.Name)
+		putNotEmptyStr(attrs, "feedback.email", sr.scrubEmail(feedback.Email))
+		putNotEmptyStr(attrs, "feedback.message", feedback.Message)
+		putNotEmptyStr(attrs, "feedback.url", feedback.Url)
+		putNotEmptyStr(attrs, "feedback.source", feedback.Source)
+		putNotEmptyStr(attrs, "feedback.associated_event_id", feedback.AssociatedEventId)
+		putNotEmptyStr(attrs, "feedback.replay_id", feedback.ReplayId)
+	}
+}
+
+func putNotEmptyStr(attrs pcommon.Map, key string, value string) {
+	if value != "" {
+		attrs.PutStr(key, value)
+	}
+}
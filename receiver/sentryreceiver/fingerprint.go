@@ -0,0 +1,139 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// groupingStrategyClient is put to issue.grouping attribute, when the fingerprint of the SDK is used
+const groupingStrategyClient = "client"
+
+// groupingStrategies are the versions of the grouping algorithm, which return the components of the fingerprint.
+// The released versions must never be changed, so that the fingerprints stay stable across collector upgrades.
+// The changed algorithm must be added as a new version.
+var groupingStrategies = map[string]func(event models.Event) []string{
+	"v1": groupingComponentsV1,
+}
+
+// Variable parts of the messages, which are replaced by placeholders, so that the messages
+// of the same issue with e.g. different ids get the same fingerprint
+var messageNormalizersV1 = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\b[\w.+-]+@[\w-]+(\.[\w-]+)+\b`), "<email>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}\b`), "<ip>"},
+	{regexp.MustCompile(`\b0[xX][0-9a-fA-F]+\b|\b[0-9a-fA-F]{8,}\b`), "<hex>"},
+	{regexp.MustCompile(`\d+`), "<int>"},
+}
+
+// maxInAppFramesV1 is the number of the most recent frames, which are the part of the fingerprint
+const maxInAppFramesV1 = 5
+
+// groupingComponentsV1 groups the exceptions by the type, the normalized message and the most recent in-app frames
+// of the last exception. The messages are grouped by the message template (logentry.message) or the normalized message.
+func groupingComponentsV1(event models.Event) []string {
+	if len(event.Exception.Values) > 0 {
+		exception := event.Exception.Values[len(event.Exception.Values)-1]
+		components := []string{"exception", exception.Type, normalizeMessageV1(string(exception.Value))}
+		return append(components, stacktraceComponentsV1(exception.Stacktrace)...)
+	}
+	message := string(event.Message)
+	if event.LogEntry != nil {
+		if template := string(event.LogEntry.Message); template != "" {
+			message = template
+		} else if formatted := event.LogEntry.Format(); formatted != "" {
+			message = formatted
+		}
+	}
+	return []string{"message", event.Logger, normalizeMessageV1(message)}
+}
+
+// stacktraceComponentsV1 returns the module or the file and the function of the most recent in-app frames.
+// Line numbers are not used, because they are changed by the unrelated changes of the code.
+// If there are no in-app frames, all frames are used.
+func stacktraceComponentsV1(stacktrace models.Stacktrace) []string {
+	frames := make([]models.StackFrame, 0, len(stacktrace.Frames))
+	for _, frame := range stacktrace.Frames {
+		if frame.InApp {
+			frames = append(frames, frame)
+		}
+	}
+	if len(frames) == 0 {
+		frames = stacktrace.Frames
+	}
+	components := make([]string, 0, maxInAppFramesV1)
+	for i := len(frames) - 1; i >= 0 && len(components) < maxInAppFramesV1; i-- {
+		location := frames[i].Module
+		if location == "" {
+			location = normalizeMessageV1(frames[i].Filename)
+		}
+		components = append(components, location+":"+frames[i].Function)
+	}
+	return components
+}
+
+func normalizeMessageV1(message string) string {
+	for _, normalizer := range messageNormalizersV1 {
+		message = normalizer.pattern.ReplaceAllString(message, normalizer.replacement)
+	}
+	return strings.TrimSpace(message)
+}
+
+// isDefaultFingerprintComponent checks for the placeholder of the default grouping in the fingerprint of the SDK
+func isDefaultFingerprintComponent(component string) bool {
+	return strings.ReplaceAll(component, " ", "") == "{{default}}"
+}
+
+// getIssueFingerprint returns the fingerprint of the issue and the grouping, which is used to compute it.
+// The fingerprint of the SDK is used, if it is sent, "{{ default }}" in it is replaced by the default components.
+func (sr *sentrytraceReceiver) getIssueFingerprint(event models.Event) (string, string) {
+	strategy := sr.config.FingerprintingCfg.Strategy
+	components := []string{strategy}
+	grouping := strategy
+	if sr.config.FingerprintingCfg.UseClientFingerprint && len(event.Fingerprint) > 0 {
+		grouping = groupingStrategyClient
+		components = []string{groupingStrategyClient}
+		for _, component := range event.Fingerprint {
+			if isDefaultFingerprintComponent(component) {
+				components = append(components, groupingStrategies[strategy](event)...)
+			} else {
+				components = append(components, component)
+			}
+		}
+	} else {
+		components = append(components, groupingStrategies[strategy](event)...)
+	}
+	hash := sha256.Sum256([]byte(strings.Join(components, "\n")))
+	return hex.EncodeToString(hash[:16]), grouping
+}
+
+// putIssueFingerprint adds issue.fingerprint and issue.grouping attributes to the span of the event
+func (sr *sentrytraceReceiver) putIssueFingerprint(attrs pcommon.Map, event models.Event) {
+	if !sr.config.FingerprintingCfg.Enabled {
+		return
+	}
+	fingerprint, grouping := sr.getIssueFingerprint(event)
+	attrs.PutStr("issue.fingerprint", fingerprint)
+	attrs.PutStr("issue.grouping", grouping)
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// flattener puts the nested JSON objects to the attributes. The nested maps are flattened
+// to the keys joined with ".", e.g. {"a": {"b": 1}} with prefix "data" becomes "data.a.b" = 1.
+// The types of the values are preserved, arrays are put as slices.
+type flattener struct {
+	// maxDepth limits the number of the flattened levels, the deeper objects are put as maps. 0 means no limit.
+	maxDepth int
+	// filter checks the path of the value without the prefix, e.g. "a.b". nil filter allows all values.
+	filter *keyFilter
+	// putValue puts the flattened value to the attributes. nil means putAttributeValue.
+	putValue func(attrs pcommon.Map, key string, value interface{})
+}
+
+func newFlattener(maxDepth int, include []string, exclude []string) (*flattener, error) {
+	filter, err := newKeyFilter(include, exclude)
+	if err != nil {
+		return nil, err
+	}
+	return &flattener{maxDepth: maxDepth, filter: filter}, nil
+}
+
+func (f *flattener) put(attrs pcommon.Map, prefix string, value interface{}) {
+	f.putPath(attrs, prefix, "", value, 1)
+}
+
+func (f *flattener) putPath(attrs pcommon.Map, prefix string, path string, value interface{}, depth int) {
+	if value == nil {
+		return
+	}
+	valueMap, isMap := value.(map[string]interface{})
+	if isMap && (f.maxDepth == 0 || depth <= f.maxDepth) {
+		for k, v := range valueMap {
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			f.putPath(attrs, prefix, childPath, v, depth+1)
+		}
+		return
+	}
+	if f.filter != nil && !f.filter.allowed(path) {
+		return
+	}
+	key := prefix
+	if path != "" {
+		key = prefix + "." + path
+	}
+	if f.putValue != nil {
+		f.putValue(attrs, key, value)
+		return
+	}
+	putAttributeValue(attrs, key, value)
+}
+
+// putFlattenedAttributes flattens the value without limits
+func putFlattenedAttributes(attrs pcommon.Map, key string, value interface{}) {
+	(&flattener{}).put(attrs, key, value)
+}
+
+// putAttributeValue puts the JSON value to the attributes with the corresponding attribute type
+func putAttributeValue(attrs pcommon.Map, key string, value interface{}) {
+	setAttributeValue(attrs.PutEmpty(key), value)
+}
+
+func setAttributeValue(dest pcommon.Value, value interface{}) {
+	switch valTyped := value.(type) {
+	case nil:
+		return
+	case string:
+		dest.SetStr(valTyped)
+	case bool:
+		dest.SetBool(valTyped)
+	case float64:
+		dest.SetDouble(valTyped)
+	case int64:
+		dest.SetInt(valTyped)
+	case json.Number:
+		if intValue, err := valTyped.Int64(); err == nil {
+			dest.SetInt(intValue)
+		} else if floatValue, err := valTyped.Float64(); err == nil {
+			dest.SetDouble(floatValue)
+		} else {
+			dest.SetStr(valTyped.String())
+		}
+	case []interface{}:
+		slice := dest.SetEmptySlice()
+		for _, item := range valTyped {
+			setAttributeValue(slice.AppendEmpty(), item)
+		}
+	case map[string]interface{}:
+		valueMap := dest.SetEmptyMap()
+		for k, v := range valTyped {
+			setAttributeValue(valueMap.PutEmpty(k), v)
+		}
+	default:
+		dest.SetStr(fmt.Sprintf("%v", valTyped))
+	}
+}
+
+// keyFilter checks the dotted keys against allow and deny patterns. The pattern can contain "*",
+// which matches any sequence of characters. The pattern also matches all nested keys,
+// e.g. "device" matches "device.memory_size".
+type keyFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+func newKeyFilter(include []string, exclude []string) (*keyFilter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+	var err error
+	filter := &keyFilter{}
+	if filter.include, err = compileKeyPatterns(include); err != nil {
+		return nil, err
+	}
+	if filter.exclude, err = compileKeyPatterns(exclude); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+func compileKeyPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	result := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		expr := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+		re, err := regexp.Compile("^" + expr + `(\..*)?$`)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %v is not valid : %+v", pattern, err)
+		}
+		result = append(result, re)
+	}
+	return result, nil
+}
+
+func (f *keyFilter) allowed(key string) bool {
+	if len(f.include) > 0 && !matchesAnyKeyPattern(key, f.include) {
+		return false
+	}
+	return !matchesAnyKeyPattern(key, f.exclude)
+}
+
+func matchesAnyKeyPattern(key string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
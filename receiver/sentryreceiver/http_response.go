@@ -0,0 +1,98 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Prefix of the message which confighttp passes to the error handler
+// when the request has a Content-Encoding without a registered decoder.
+const unsupportedContentEncodingMsg = "unsupported Content-Encoding"
+
+// Content types accepted on the envelope endpoint. Browser SDKs send envelopes
+// via fetch or navigator.sendBeacon with text/plain to avoid CORS preflight requests.
+var supportedContentTypes = map[string]bool{
+	"":                              true,
+	"application/x-sentry-envelope": true,
+	"text/plain":                    true,
+}
+
+type errorResponse struct {
+	Detail string `json:"detail"`
+}
+
+// writeErrorResponse writes the error in the same form as Sentry Relay does: {"detail": "..."}
+func writeErrorResponse(w http.ResponseWriter, statusCode int, detail string) {
+	body, err := json.Marshal(errorResponse{Detail: detail})
+	if err != nil {
+		body = []byte("{}")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
+
+// writeSuccessResponse answers with the event id of the envelope. If some items of the envelope are skipped
+// by the lenient parsing, the id is returned only if the item with this event id is accepted.
+func writeSuccessResponse(w http.ResponseWriter, envlp *models.EnvelopEventParseResult) {
+	eventId := envlp.EnvelopEventHeader.EventID
+	if envlp.EnvelopType == models.ENVELOP_TYPE_SESSION || eventId == "" ||
+		(len(envlp.ItemErrors) > 0 && !envlp.IsEventAccepted(eventId)) {
+		_, _ = w.Write([]byte("{}"))
+		return
+	}
+	_, _ = w.Write([]byte(fmt.Sprintf("{\"id\": \"%v\"}", eventId)))
+}
+
+// serverErrorHandler is invoked by confighttp when the request body can not be decompressed
+func serverErrorHandler(w http.ResponseWriter, _ *http.Request, errorMsg string, statusCode int) {
+	if strings.HasPrefix(errorMsg, unsupportedContentEncodingMsg) {
+		statusCode = http.StatusUnsupportedMediaType
+	}
+	writeErrorResponse(w, statusCode, errorMsg)
+}
+
+func isSupportedContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return supportedContentTypes[strings.ToLower(mediaType)]
+}
+
+func newBrotliReader(body io.ReadCloser) (io.ReadCloser, error) {
+	return io.NopCloser(brotli.NewReader(body)), nil
+}
+
+func newZstdReader(body io.ReadCloser) (io.ReadCloser, error) {
+	zr, err := zstd.NewReader(body, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return zr.IOReadCloser(), nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package sentryreceiver
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const metricsScopeName = "otelcol/sentryreceiver"
+
+func (sr *sentrytraceReceiver) consumeMetrics(ctx context.Context, envlp *models.EnvelopEventParseResult, r *http.Request) error {
+	if len(envlp.Metrics) == 0 {
+		return nil
+	}
+	if sr.metricsConsumer == nil {
+		sr.logger.Sugar().Debugf("SentryReceiver : %v statsd metrics are skipped, because the metrics pipeline is not configured", len(envlp.Metrics))
+		return nil
+	}
+	ctx = sr.obsrecvr.StartMetricsOp(ctx)
+	md := sr.toMetrics(envlp, r)
+	err := sr.metricsConsumer.ConsumeMetrics(ctx, md)
+	sr.obsrecvr.EndMetricsOp(ctx, "sentryReceiverTagValue", md.DataPointCount(), err)
+	return err
+}
+
+func (sr *sentrytraceReceiver) toMetrics(envlp *models.EnvelopEventParseResult, r *http.Request) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	resourceMetrics := md.ResourceMetrics().AppendEmpty()
+	resource := resourceMetrics.Resource()
+	// statsd metrics carry no release and environment of their own, so the resource takes them from the trace context of the envelope header
+	sr.fillResource(&resource, envlp, r, resourceKey{
+		release:     envlp.EnvelopEventHeader.Trace.Release,
+		environment: envlp.EnvelopEventHeader.Trace.Environment,
+	}, "")
+	scopeMetrics := resourceMetrics.ScopeMetrics().AppendEmpty()
+	scopeMetrics.Scope().SetName(metricsScopeName)
+
+	serviceName := sr.GetServiceName(r)
+	now := time.Now()
+	metricsByName := make(map[string]pmetric.Metric)
+	for _, statsdMetric := range envlp.Metrics {
+		name := sr.config.StatsdMetricsCfg.NamePrefix + statsdMetric.Name
+		key := name + "|" + statsdMetric.Type + "|" + statsdMetric.Unit
+		metric, ok := metricsByName[key]
+		if !ok {
+			metric = scopeMetrics.Metrics().AppendEmpty()
+			metric.SetName(name)
+			metric.SetUnit(statsdMetric.Unit)
+			metric.SetDescription("Sentry custom metric " + statsdMetric.Name)
+			switch statsdMetric.Type {
+			case models.STATSD_TYPE_COUNTER:
+				sum := metric.SetEmptySum()
+				sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+				sum.SetIsMonotonic(true)
+			case models.STATSD_TYPE_DISTRIBUTION:
+				metric.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+			case models.STATSD_TYPE_SET, models.STATSD_TYPE_GAUGE:
+				metric.SetEmptyGauge()
+			}
+			metricsByName[key] = metric
+		}
+
+		timestamp := pcommon.NewTimestampFromTime(now)
+		if statsdMetric.Timestamp > 0 {
+			timestamp = pcommon.NewTimestampFromTime(time.Unix(statsdMetric.Timestamp, 0))
+		}
+
+		var attrs pcommon.Map
+		switch statsdMetric.Type {
+		case models.STATSD_TYPE_COUNTER:
+			dataPoint := metric.Sum().DataPoints().AppendEmpty()
+			dataPoint.SetStartTimestamp(timestamp)
+			dataPoint.SetTimestamp(timestamp)
+			var value float64
+			for _, v := range statsdMetric.Values {
+				value += v
+			}
+			dataPoint.SetDoubleValue(value)
+			attrs = dataPoint.Attributes()
+		case models.STATSD_TYPE_DISTRIBUTION:
+			dataPoint := metric.Histogram().DataPoints().AppendEmpty()
+			dataPoint.SetStartTimestamp(timestamp)
+			dataPoint.SetTimestamp(timestamp)
+			sr.fillHistogramDataPoint(dataPoint, statsdMetric.Values)
+			attrs = dataPoint.Attributes()
+		case models.STATSD_TYPE_SET:
+			dataPoint := metric.Gauge().DataPoints().AppendEmpty()
+			dataPoint.SetTimestamp(timestamp)
+			dataPoint.SetIntValue(int64(countUnique(statsdMetric.SetValues)))
+			attrs = dataPoint.Attributes()
+		case models.STATSD_TYPE_GAUGE:
+			// Aggregated gauges are sent as last:min:max:sum:count, the last value is exposed
+			dataPoint := metric.Gauge().DataPoints().AppendEmpty()
+			dataPoint.SetTimestamp(timestamp)
+			dataPoint.SetDoubleValue(statsdMetric.Values[0])
+			attrs = dataPoint.Attributes()
+		}
+
+		for k, v := range statsdMetric.Tags {
+			attrs.PutStr(k, v)
+		}
+		if serviceName != "" {
+			attrs.PutStr("service_name", serviceName)
+		}
+	}
+	return md
+}
+
+func (sr *sentrytraceReceiver) fillHistogramDataPoint(dataPoint pmetric.HistogramDataPoint, values []float64) {
+	buckets := sr.config.StatsdMetricsCfg.DistributionBuckets
+	bucketCounts := make([]uint64, len(buckets)+1)
+	var sum float64
+	minValue, maxValue := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		sum += v
+		minValue = math.Min(minValue, v)
+		maxValue = math.Max(maxValue, v)
+		bucketCounts[sort.SearchFloat64s(buckets, v)]++
+	}
+	dataPoint.SetCount(uint64(len(values)))
+	dataPoint.SetSum(sum)
+	if len(values) > 0 {
+		dataPoint.SetMin(minValue)
+		dataPoint.SetMax(maxValue)
+	}
+	dataPoint.ExplicitBounds().FromRaw(buckets)
+	dataPoint.BucketCounts().FromRaw(bucketCounts)
+}
+
+func countUnique(values []string) int {
+	unique := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		unique[v] = struct{}{}
+	}
+	return len(unique)
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"strings"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	conventions "go.opentelemetry.io/collector/semconv/v1.9.0"
+)
+
+// Kinds of the error events, which are put to error.kind attribute
+const (
+	errorKindAnr   = "anr"
+	errorKindCrash = "crash"
+	errorKindError = "error"
+)
+
+// SDKs of the mobile applications, e.g. "sentry.java.android", "sentry.javascript.react-native"
+var mobileSdkNames = []string{
+	"sentry.java.android",
+	"sentry.cocoa",
+	"sentry.javascript.react-native",
+	"sentry.dart.flutter",
+	"sentry.dotnet.maui",
+	"sentry.kotlin.multiplatform",
+	"sentry.native.android",
+}
+
+var mobileOsNames = map[string]bool{
+	"android": true,
+	"ios":     true,
+	"ipados":  true,
+}
+
+// Mechanism types of ANR (Application Not Responding) and app hang events of Android and iOS SDKs
+var anrMechanismTypes = map[string]bool{
+	"anr":            true,
+	"anrv2":          true,
+	"anr_foreground": true,
+	"anr_background": true,
+	"apphang":        true,
+}
+
+// isMobileEvent checks whether the event is sent by the mobile SDK. The SDK name is taken from the event
+// or from the envelope header, the OS name from the os context.
+func isMobileEvent(event models.Event, envlp *models.EnvelopEventParseResult) bool {
+	sdkName := event.Sdk.Name
+	if sdkName == "" {
+		sdkName = envlp.EnvelopEventHeader.SdkInfo.Name
+	}
+	for _, mobileSdkName := range mobileSdkNames {
+		if strings.HasPrefix(sdkName, mobileSdkName) {
+			return true
+		}
+	}
+	return mobileOsNames[strings.ToLower(event.Contexts.Os.Name)]
+}
+
+// putMobileAttributes adds device, OS and application attributes of the mobile event to the span
+func putMobileAttributes(attrs pcommon.Map, contexts models.EventContexts) {
+	device := contexts.Device
+	putNotEmptyStr(attrs, conventions.AttributeDeviceModelName, device.Model)
+	putNotEmptyStr(attrs, conventions.AttributeDeviceModelIdentifier, device.ModelId)
+	putNotEmptyStr(attrs, conventions.AttributeDeviceManufacturer, device.Manufacturer)
+	putNotEmptyStr(attrs, "device.family", device.Family)
+	putNotEmptyStr(attrs, "device.brand", device.Brand)
+	putNotEmptyStr(attrs, "device.arch", device.Arch)
+	if device.Simulator {
+		attrs.PutBool("device.simulator", true)
+	}
+
+	putNotEmptyStr(attrs, conventions.AttributeOSName, contexts.Os.Name)
+	putNotEmptyStr(attrs, conventions.AttributeOSVersion, string(contexts.Os.Version))
+	putNotEmptyStr(attrs, "os.build", string(contexts.Os.Build))
+
+	app := contexts.App
+	putNotEmptyStr(attrs, "app.identifier", app.AppIdentifier)
+	putNotEmptyStr(attrs, "app.name", app.AppName)
+	putNotEmptyStr(attrs, "app.version", string(app.AppVersion))
+	putNotEmptyStr(attrs, "app.build", string(app.AppBuild))
+	if app.InForeground != nil {
+		attrs.PutBool("app.in_foreground", *app.InForeground)
+	}
+}
+
+// getErrorKind classifies the error event of the mobile application. ANR is detected by the exception mechanism,
+// the crash is the fatal event or the exception, which is not handled by the application.
+func (sr *sentrytraceReceiver) getErrorKind(event models.Event) string {
+	kind := ""
+	if sr.isErrorEvent(event) {
+		kind = errorKindError
+	}
+	if sr.evaluateLevel(event) == "fatal" {
+		kind = errorKindCrash
+	}
+	for _, exception := range event.Exception.Values {
+		mechanism := exception.Mechanism
+		if anrMechanismTypes[strings.ToLower(mechanism.Type)] || exception.Type == "ApplicationNotResponding" {
+			return errorKindAnr
+		}
+		// missing handled means that it is unknown, whether the exception is handled
+		if mechanism.Type != "" && mechanism.Handled != nil && !*mechanism.Handled {
+			kind = errorKindCrash
+		}
+	}
+	return kind
+}
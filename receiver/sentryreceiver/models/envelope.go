@@ -15,7 +15,12 @@
 package models
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const (
@@ -23,6 +28,8 @@ const (
 	ENVELOP_TYPE_TRANSACTION = 1
 	ENVELOP_TYPE_EVENT       = 2
 	ENVELOP_TYPE_SESSION     = 3
+	ENVELOP_TYPE_SPAN        = 4
+	ENVELOP_TYPE_FEEDBACK    = 5
 )
 
 type SdkInfo struct {
@@ -31,12 +38,42 @@ type SdkInfo struct {
 }
 
 type EnvelopTypeHeader struct {
-	Type   string `json:"type"`
-	Length int    `json:"length,omitempty"`
+	Type           string `json:"type"`
+	Length         int    `json:"length,omitempty"`
+	Filename       string `json:"filename,omitempty"`
+	ContentType    string `json:"content_type,omitempty"`
+	AttachmentType string `json:"attachment_type,omitempty"`
+}
+
+type Attachment struct {
+	Filename       string
+	ContentType    string
+	AttachmentType string
+	Data           []byte
+}
+
+// StoredAttachment describes the attachment saved to the attachments store
+type StoredAttachment struct {
+	Filename    string
+	ContentType string
+	Location    string
+	Size        int
 }
 type EnvelopEventHeader struct {
 	SdkInfo `json:"sdk,omitempty"`
-	EventID string `json:"event_id,omitempty"`
+	EventID string              `json:"event_id,omitempty"`
+	Dsn     string              `json:"dsn,omitempty"`
+	Trace   EnvelopTraceContext `json:"trace,omitempty"`
+	// SentAt is the time in RFC 3339 format, when the SDK sent the envelope
+	SentAt string `json:"sent_at,omitempty"`
+}
+
+// EnvelopTraceContext is the dynamic sampling context, which SDKs put to the envelope header
+type EnvelopTraceContext struct {
+	TraceId     string `json:"trace_id,omitempty"`
+	PublicKey   string `json:"public_key,omitempty"`
+	Release     string `json:"release,omitempty"`
+	Environment string `json:"environment,omitempty"`
 }
 
 type EventMeasurement struct {
@@ -47,78 +84,257 @@ type EventMeasurements map[string]EventMeasurement
 
 type StrongString string
 
+// JSONObject keeps the numbers as json.Number, so that the integers and the floats can be distinguished
+type JSONObject map[string]interface{}
+
+func (o *JSONObject) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var values map[string]interface{}
+	if err := decoder.Decode(&values); err != nil {
+		return err
+	}
+	*o = values
+	return nil
+}
+
 type Breadcrumb struct {
 	Type      string                 `json:"type,omitempty"`
 	Level     string                 `json:"level,omitempty"`
 	Message   StrongString           `json:"message,omitempty"`
 	Data      map[string]interface{} `json:"data,omitempty"`
 	Category  string                 `json:"category,omitempty"`
-	Timestamp float64                `json:"timestamp,omitempty"`
+	Timestamp Timestamp              `json:"timestamp,omitempty"`
 }
 
-func (d *StrongString) UnmarshalJSON(data []byte) error {
-	formattedData := StrongString(string(data))
-	d = &formattedData
+// Breadcrumbs are sent either as a list or as an object {"values": [...]}
+type Breadcrumbs []Breadcrumb
+
+func (b *Breadcrumbs) UnmarshalJSON(data []byte) error {
+	var values []Breadcrumb
+	if err := json.Unmarshal(data, &values); err == nil {
+		*b = values
+		return nil
+	}
+	var wrapped struct {
+		Values []Breadcrumb `json:"values"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return err
+	}
+	*b = wrapped.Values
 	return nil
 }
 
-type EventRequest struct {
-	URL     string            `json:"url,omitempty"`
-	Headers map[string]string `json:"headers,omitempty"`
+// UnmarshalJSON decodes JSON string as is. Other JSON values (objects, arrays, numbers and booleans)
+// are kept as the compact JSON text, null is decoded as the empty string.
+func (d *StrongString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = ""
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*d = StrongString(str)
+		return nil
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, data); err != nil {
+		return err
+	}
+	*d = StrongString(compacted.String())
+	return nil
 }
 
 type Event struct {
 	Message        StrongString                `json:"message,omitempty"`
+	LogEntry       *LogEntry                   `json:"logentry,omitempty"`
 	Level          string                      `json:"level,omitempty"`
 	EventId        string                      `json:"event_id,omitempty"`
 	Platform       string                      `json:"platform,omitempty"`
 	Dist           string                      `json:"dist,omitempty"`
-	Timestamp      float64                     `json:"timestamp,omitempty"`
-	StartTimestamp float64                     `json:"start_timestamp,omitempty"`
+	Timestamp      Timestamp                   `json:"timestamp,omitempty"`
+	StartTimestamp Timestamp                   `json:"start_timestamp,omitempty"`
 	Environment    string                      `json:"environment,omitempty"`
 	Release        string                      `json:"release,omitempty"`
 	Transaction    string                      `json:"transaction,omitempty"`
 	Measurements   map[string]EventMeasurement `json:"measurements,omitempty"`
-	Breadcrumbs    []Breadcrumb                `json:"breadcrumbs,omitempty"`
+	Breadcrumbs    Breadcrumbs                 `json:"breadcrumbs,omitempty"`
 	User           EventUser                   `json:"user,omitempty"`
 	Contexts       EventContexts               `json:"contexts,omitempty"`
-	Tags           map[string]interface{}      `json:"tags,omitempty"`
+	Tags           JSONObject                  `json:"tags,omitempty"`
 	Spans          []EventSpan                 `json:"spans,omitempty"`
 	Request        EventRequest                `json:"request,omitempty"`
 	Sdk            SdkInfo                     `json:"sdk,omitempty"`
 	Exception      EventException              `json:"exception,omitempty"`
 	Logger         string                      `json:"logger,omitempty"`
+	Extra          JSONObject                  `json:"extra,omitempty"`
+	Modules        map[string]string           `json:"modules,omitempty"`
+	Threads        Threads                     `json:"threads,omitempty"`
+	Fingerprint    []string                    `json:"fingerprint,omitempty"`
+	ServerName     string                      `json:"server_name,omitempty"`
+	DebugMeta      DebugMeta                   `json:"debug_meta,omitempty"`
+}
+
+// UserReport is the legacy user feedback item, which is sent for the already captured event
+type UserReport struct {
+	EventId  string `json:"event_id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Comments string `json:"comments,omitempty"`
+}
+
+// UserFeedback is the common representation of the feedback item and the legacy user_report item
+type UserFeedback struct {
+	Name              string
+	Email             string
+	Message           string
+	Url               string
+	Source            string
+	AssociatedEventId string
+	ReplayId          string
+	// Event is set for the feedback item only
+	Event *Event
+}
+
+func NewUserFeedbackFromEvent(event *Event) UserFeedback {
+	feedback := event.Contexts.Feedback
+	return UserFeedback{
+		Name:              feedback.Name,
+		Email:             feedback.ContactEmail,
+		Message:           feedback.Message,
+		Url:               feedback.Url,
+		Source:            feedback.Source,
+		AssociatedEventId: feedback.AssociatedEventId,
+		ReplayId:          feedback.ReplayId,
+		Event:             event,
+	}
+}
+
+func NewUserFeedbackFromUserReport(report *UserReport) UserFeedback {
+	return UserFeedback{
+		Name:              report.Name,
+		Email:             report.Email,
+		Message:           report.Comments,
+		AssociatedEventId: report.EventId,
+	}
 }
 
 type SessionEvent struct {
-	Status    string `json:"status,omitempty"`
-	Sid       string `json:"sid,omitempty"`
-	Timestamp string `json:"timestamp,omitempty"`
+	Status    string            `json:"status,omitempty"`
+	Sid       string            `json:"sid,omitempty"`
+	Seq       int64             `json:"seq,omitempty"`
+	Init      bool              `json:"init,omitempty"`
+	Started   Timestamp         `json:"started,omitempty"`
+	Timestamp Timestamp         `json:"timestamp,omitempty"`
+	Duration  *float64          `json:"duration,omitempty"`
+	Errors    int               `json:"errors,omitempty"`
+	Attrs     SessionAttributes `json:"attrs,omitempty"`
+}
+
+type SessionAttributes struct {
+	Release     string `json:"release,omitempty"`
+	Environment string `json:"environment,omitempty"`
+}
+
+type StackFrame struct {
+	Filename string `json:"filename,omitempty"`
+	Function string `json:"function,omitempty"`
+	Module   string `json:"module,omitempty"`
+	InApp    bool   `json:"in_app,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	Colno    int    `json:"colno,omitempty"`
+}
+
+// Stacktrace contains the frames from the oldest to the most recent one
+type Stacktrace struct {
+	Frames []StackFrame `json:"frames,omitempty"`
 }
 
 type EventException struct {
 	Values []struct {
 		Type       string       `json:"type,omitempty"`
 		Value      StrongString `json:"value,omitempty"`
-		Stacktrace struct {
-			Frames []struct {
-				Filename string `json:"filename,omitempty"`
-				Function string `json:"function,omitempty"`
-				InApp    bool   `json:"in_app,omitempty"`
-				Lineno   int    `json:"lineno,omitempty"`
-				Colno    int    `json:"colno,omitempty"`
-			} `json:"frames,omitempty"`
-		} `json:"stacktrace,omitempty"`
-		Mechanism struct {
+		Stacktrace Stacktrace   `json:"stacktrace,omitempty"`
+		Mechanism  struct {
 			Type      string `json:"type,omitempty"`
-			Handled   bool   `json:"handled,omitempty"`
+			Handled   *bool  `json:"handled,omitempty"`
 			Synthetic bool   `json:"synthetic,omitempty"`
 		} `json:"mechanism,omitempty"`
 	} `json:"values,omitempty"`
 }
 
+// Format returns a readable representation of the exception values. Handled is printed as true, false or unset
+// because the mechanism keeps it as a pointer to tell the explicit false from the missing value.
+func (e *EventException) Format() string {
+	var result strings.Builder
+	result.WriteByte('[')
+	for i, value := range e.Values {
+		if i > 0 {
+			result.WriteByte(' ')
+		}
+		handled := "unset"
+		if value.Mechanism.Handled != nil {
+			handled = strconv.FormatBool(*value.Mechanism.Handled)
+		}
+		result.WriteString(fmt.Sprintf("{Type:%s Value:%s Stacktrace:%+v Mechanism:{Type:%s Handled:%s Synthetic:%t}}",
+			value.Type, value.Value, value.Stacktrace, value.Mechanism.Type, handled, value.Mechanism.Synthetic))
+	}
+	result.WriteByte(']')
+	return result.String()
+}
+
+type Thread struct {
+	// Id is a number or a string
+	Id         interface{} `json:"id,omitempty"`
+	Name       string      `json:"name,omitempty"`
+	Crashed    bool        `json:"crashed,omitempty"`
+	Current    bool        `json:"current,omitempty"`
+	Main       bool        `json:"main,omitempty"`
+	State      string      `json:"state,omitempty"`
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+// Threads are sent either as a list or as an object {"values": [...]}
+type Threads []Thread
+
+func (t *Threads) UnmarshalJSON(data []byte) error {
+	var values []Thread
+	if err := json.Unmarshal(data, &values); err == nil {
+		*t = values
+		return nil
+	}
+	var wrapped struct {
+		Values []Thread `json:"values"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return err
+	}
+	*t = wrapped.Values
+	return nil
+}
+
+// DebugMeta contains the debug images (e.g. dSYM, ELF, source maps), which are used to symbolicate the stack traces
+type DebugMeta struct {
+	Images  []map[string]interface{} `json:"images,omitempty"`
+	SdkInfo map[string]interface{}   `json:"sdk_info,omitempty"`
+}
+
+// EventUser is the user of the application. The id can be a string or a number.
+// ip_address "{{auto}}" means that the IP address must be inferred from the connection.
 type EventUser struct {
-	Id string `json:"id,omitempty"`
+	Id        StrongString `json:"id,omitempty"`
+	Email     string       `json:"email,omitempty"`
+	Username  string       `json:"username,omitempty"`
+	IpAddress string       `json:"ip_address,omitempty"`
+	Segment   string       `json:"segment,omitempty"`
+	Geo       UserGeo      `json:"geo,omitempty"`
+}
+
+type UserGeo struct {
+	City        string `json:"city,omitempty"`
+	CountryCode string `json:"country_code,omitempty"`
+	Region      string `json:"region,omitempty"`
 }
 
 type EventContexts struct {
@@ -127,8 +343,38 @@ type EventContexts struct {
 		SpanID  string `json:"span_id,omitempty"`
 		TraceID string `json:"trace_id,omitempty"`
 	} `json:"trace,omitempty"`
-	Error ContextError           `json:"Error,omitempty"`
-	AsMap map[string]interface{} `json:"-"`
+	Error    ContextError           `json:"Error,omitempty"`
+	Feedback ContextFeedback        `json:"feedback,omitempty"`
+	Device   ContextDevice          `json:"device,omitempty"`
+	Os       ContextOs              `json:"os,omitempty"`
+	App      ContextApp             `json:"app,omitempty"`
+	AsMap    map[string]interface{} `json:"-"`
+}
+
+// ContextDevice is the device context of the mobile and desktop SDKs
+type ContextDevice struct {
+	Family       string `json:"family,omitempty"`
+	Model        string `json:"model,omitempty"`
+	ModelId      string `json:"model_id,omitempty"`
+	Manufacturer string `json:"manufacturer,omitempty"`
+	Brand        string `json:"brand,omitempty"`
+	Arch         string `json:"arch,omitempty"`
+	Simulator    bool   `json:"simulator,omitempty"`
+}
+
+type ContextOs struct {
+	Name    string       `json:"name,omitempty"`
+	Version StrongString `json:"version,omitempty"`
+	Build   StrongString `json:"build,omitempty"`
+}
+
+// ContextApp is the application context of the mobile SDKs
+type ContextApp struct {
+	AppIdentifier string       `json:"app_identifier,omitempty"`
+	AppName       string       `json:"app_name,omitempty"`
+	AppVersion    StrongString `json:"app_version,omitempty"`
+	AppBuild      StrongString `json:"app_build,omitempty"`
+	InForeground  *bool        `json:"in_foreground,omitempty"`
 }
 
 type _EventContexts EventContexts
@@ -142,14 +388,27 @@ func (f *EventContexts) UnmarshalJSON(bs []byte) (err error) {
 		return err
 	}
 
+	// json.Number keeps integer values of the contexts as integers
 	asMap := make(map[string]interface{})
-	if err = json.Unmarshal(bs, &asMap); err == nil {
+	decoder := json.NewDecoder(bytes.NewReader(bs))
+	decoder.UseNumber()
+	if err = decoder.Decode(&asMap); err == nil {
 		f.AsMap = asMap
 	}
 
 	return err
 }
 
+type ContextFeedback struct {
+	Message           string `json:"message,omitempty"`
+	ContactEmail      string `json:"contact_email,omitempty"`
+	Name              string `json:"name,omitempty"`
+	Url               string `json:"url,omitempty"`
+	Source            string `json:"source,omitempty"`
+	AssociatedEventId string `json:"associated_event_id,omitempty"`
+	ReplayId          string `json:"replay_id,omitempty"`
+}
+
 type ContextError struct {
 	Config       ContextErrorConfig   `json:"config,omitempty"`
 	Request      ContextErrorRequest  `json:"request,omitempty"`
@@ -189,23 +448,63 @@ type ContextErrorResponse struct {
 }
 
 type EventSpan struct {
-	Description    string                 `json:"description"`
-	SpanId         string                 `json:"span_id"`
-	ParentSpanId   string                 `json:"parent_span_id"`
-	Origin         string                 `json:"origin"`
-	Op             string                 `json:"op,omitempty"`
-	Tags           map[string]interface{} `json:"tags,omitempty"`
-	Status         string                 `json:"status,omitempty"`
-	Data           map[string]interface{} `json:"data,omitempty"`
-	TraceId        string                 `json:"trace_id"`
-	Timestamp      float64                `json:"timestamp,omitempty"`
-	StartTimestamp float64                `json:"start_timestamp,omitempty"`
+	Description    string            `json:"description"`
+	SpanId         string            `json:"span_id"`
+	ParentSpanId   string            `json:"parent_span_id"`
+	Origin         string            `json:"origin"`
+	Op             string            `json:"op,omitempty"`
+	Tags           JSONObject        `json:"tags,omitempty"`
+	Status         string            `json:"status,omitempty"`
+	Data           JSONObject        `json:"data,omitempty"`
+	TraceId        string            `json:"trace_id"`
+	Timestamp      Timestamp         `json:"timestamp,omitempty"`
+	StartTimestamp Timestamp         `json:"start_timestamp,omitempty"`
+	SegmentId      string            `json:"segment_id,omitempty"`
+	IsSegment      bool              `json:"is_segment,omitempty"`
+	ExclusiveTime  float64           `json:"exclusive_time,omitempty"`
+	Measurements   EventMeasurements `json:"measurements,omitempty"`
 }
 
 type EnvelopEventParseResult struct {
 	EnvelopTypeHeader  `json:"type_header,omitempty"`
 	EnvelopEventHeader `json:"header,omitempty"`
-	Events             []Event        `json:"events,omitempty"`
-	SessionEvents      []SessionEvent `json:"session-events,omitempty"`
-	EnvelopType        int            `json:"envelop-type,omitempty"`
+	Events             []Event            `json:"events,omitempty"`
+	SessionEvents      []SessionEvent     `json:"session-events,omitempty"`
+	Metrics            []StatsdMetric     `json:"metrics,omitempty"`
+	Spans              []EventSpan        `json:"spans,omitempty"`
+	Feedbacks          []UserFeedback     `json:"feedbacks,omitempty"`
+	Attachments        []Attachment       `json:"-"`
+	StoredAttachments  []StoredAttachment `json:"-"`
+	Tenant             string             `json:"-"`
+	// ItemErrors contains the errors of the items, which are skipped by the lenient parsing
+	ItemErrors []error `json:"-"`
+	// ClockSkew is the difference between the receive time and sent_at, which is applied to the timestamps
+	ClockSkew   time.Duration `json:"-"`
+	EnvelopType int           `json:"envelop-type,omitempty"`
+}
+
+// HasTraceData reports whether the envelope contains items which are converted to spans
+func (r *EnvelopEventParseResult) HasTraceData() bool {
+	return len(r.Events) > 0 || len(r.SessionEvents) > 0 || len(r.Spans) > 0 || len(r.Feedbacks) > 0
+}
+
+// IsEventAccepted reports whether the event, transaction or feedback item with the event id is parsed successfully
+func (r *EnvelopEventParseResult) IsEventAccepted(eventId string) bool {
+	eventId = NormalizeEventId(eventId)
+	for _, event := range r.Events {
+		if NormalizeEventId(event.EventId) == eventId {
+			return true
+		}
+	}
+	for _, feedback := range r.Feedbacks {
+		if feedback.Event != nil && NormalizeEventId(feedback.Event.EventId) == eventId {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeEventId returns the event id in the form without dashes in lower case
+func NormalizeEventId(eventId string) string {
+	return strings.ToLower(strings.ReplaceAll(eventId, "-", ""))
 }
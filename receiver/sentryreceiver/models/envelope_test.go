@@ -0,0 +1,75 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStrongStringUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected StrongString
+		wantErr  bool
+	}{
+		{name: "string", json: `"payment failed"`, expected: "payment failed"},
+		{name: "escaped string", json: `"line1\nline2 \"quoted\""`, expected: "line1\nline2 \"quoted\""},
+		{name: "empty string", json: `""`, expected: ""},
+		{name: "null", json: `null`, expected: ""},
+		{name: "number", json: `42.5`, expected: "42.5"},
+		{name: "boolean", json: `false`, expected: "false"},
+		{name: "object is compacted", json: "{ \"code\" : 500,\n \"reason\" : \"timeout\" }", expected: `{"code":500,"reason":"timeout"}`},
+		{name: "array is compacted", json: `[ 1, "two", null ]`, expected: `[1,"two",null]`},
+		{name: "invalid JSON", json: `{"code":`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value StrongString
+			err := value.UnmarshalJSON([]byte(tt.json))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error : %v", err)
+			}
+			if value != tt.expected {
+				t.Errorf("got %q, expected %q", value, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStrongStringInEvent(t *testing.T) {
+	var event Event
+	data := `{"message":{"text":"not a string"},"breadcrumbs":[{"message":"clicked"}],
+		"exception":{"values":[{"type":"Error","value":"boom"}]}}`
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatalf("unexpected error : %v", err)
+	}
+	if event.Message != `{"text":"not a string"}` {
+		t.Errorf("message = %q", event.Message)
+	}
+	if len(event.Breadcrumbs) != 1 || event.Breadcrumbs[0].Message != "clicked" {
+		t.Errorf("breadcrumbs = %+v", event.Breadcrumbs)
+	}
+	if len(event.Exception.Values) != 1 || event.Exception.Values[0].Value != "boom" {
+		t.Errorf("exception = %+v", event.Exception)
+	}
+}
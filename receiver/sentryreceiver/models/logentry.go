@@ -0,0 +1,130 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// LogEntry is the parameterized log message of the event. Params are either a list of positional parameters
+// or an object with named parameters.
+type LogEntry struct {
+	Message   StrongString `json:"message,omitempty"`
+	Formatted StrongString `json:"formatted,omitempty"`
+	Params    interface{}  `json:"params,omitempty"`
+}
+
+// Format returns the formatted message. If the SDK doesn't send the formatted message, the params are
+// substituted to the message. The placeholders are printf-like (%s, %d, %.2f, %(name)s) or "{}".
+// The placeholders without the corresponding params are kept as is.
+func (l *LogEntry) Format() string {
+	if l.Formatted != "" {
+		return string(l.Formatted)
+	}
+	message := string(l.Message)
+	positional, _ := l.Params.([]interface{})
+	named, _ := l.Params.(map[string]interface{})
+	if len(positional) == 0 && len(named) == 0 {
+		return message
+	}
+
+	var result strings.Builder
+	next := 0
+	for i := 0; i < len(message); i++ {
+		c := message[i]
+		if c == '{' && i+1 < len(message) && message[i+1] == '}' && next < len(positional) {
+			result.WriteString(fmt.Sprintf("%v", positional[next]))
+			next++
+			i++
+			continue
+		}
+		if c != '%' || i+1 >= len(message) {
+			result.WriteByte(c)
+			continue
+		}
+		if message[i+1] == '%' {
+			result.WriteByte('%')
+			i++
+			continue
+		}
+		placeholder, name, verb, ok := parsePlaceholder(message[i:])
+		if !ok {
+			result.WriteByte(c)
+			continue
+		}
+		var param interface{}
+		var found bool
+		if name != "" {
+			param, found = named[name]
+		} else if next < len(positional) {
+			param, found = positional[next], true
+			next++
+		}
+		if !found {
+			result.WriteString(placeholder)
+		} else {
+			result.WriteString(formatParam(placeholder, name, verb, param))
+		}
+		i += len(placeholder) - 1
+	}
+	return result.String()
+}
+
+// parsePlaceholder parses the placeholder at the start of the string, e.g. "%s", "%05.2f" or "%(name)s"
+func parsePlaceholder(str string) (placeholder string, name string, verb byte, ok bool) {
+	pos := 1
+	if pos < len(str) && str[pos] == '(' {
+		end := strings.IndexByte(str[pos:], ')')
+		if end < 0 {
+			return "", "", 0, false
+		}
+		name = str[pos+1 : pos+end]
+		pos += end + 1
+	}
+	for pos < len(str) && strings.IndexByte("-+ #0123456789.", str[pos]) >= 0 {
+		pos++
+	}
+	if pos >= len(str) || strings.IndexByte("sdirfFeEgGxXoc", str[pos]) < 0 {
+		return "", "", 0, false
+	}
+	return str[:pos+1], name, str[pos], true
+}
+
+func formatParam(placeholder string, name string, verb byte, param interface{}) string {
+	// the Go format is the placeholder without the name
+	format := placeholder
+	if name != "" {
+		format = "%" + placeholder[len(name)+3:]
+	}
+	flags := format[1 : len(format)-1]
+	number, isNumber := param.(float64)
+	switch verb {
+	case 'd', 'i':
+		if isNumber {
+			return fmt.Sprintf("%"+flags+"d", int64(math.Trunc(number)))
+		}
+	case 'x', 'X', 'o', 'c':
+		if isNumber {
+			return fmt.Sprintf("%"+flags+string(verb), int64(math.Trunc(number)))
+		}
+	case 'f', 'F', 'e', 'E', 'g', 'G':
+		if isNumber {
+			return fmt.Sprintf(format, number)
+		}
+	}
+	return fmt.Sprintf("%"+flags+"v", param)
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// EventRequest is the HTTP request, in which the event occurred
+type EventRequest struct {
+	URL         string         `json:"url,omitempty"`
+	Method      string         `json:"method,omitempty"`
+	QueryString RequestQuery   `json:"query_string,omitempty"`
+	Cookies     RequestCookies `json:"cookies,omitempty"`
+	Headers     RequestHeaders `json:"headers,omitempty"`
+	// Data is the request body: a string or a JSON value
+	Data interface{}             `json:"data,omitempty"`
+	Env  map[string]StrongString `json:"env,omitempty"`
+}
+
+// RequestHeaders are sent either as an object or as a list of [name, value] pairs
+type RequestHeaders map[string]string
+
+func (h *RequestHeaders) UnmarshalJSON(data []byte) error {
+	values, err := unmarshalPairs(data, nil)
+	*h = values
+	return err
+}
+
+// Get returns the value of the header, the name is case-insensitive
+func (h RequestHeaders) Get(name string) string {
+	if value, ok := h[name]; ok {
+		return value
+	}
+	for k, v := range h {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// RequestCookies are sent either as an object, as a list of [name, value] pairs or as a Cookie header string
+type RequestCookies map[string]string
+
+func (c *RequestCookies) UnmarshalJSON(data []byte) error {
+	values, err := unmarshalPairs(data, parseCookies)
+	*c = values
+	return err
+}
+
+// RequestQuery is sent either as an object, as a list of [name, value] pairs or as a query string
+type RequestQuery map[string]string
+
+func (q *RequestQuery) UnmarshalJSON(data []byte) error {
+	values, err := unmarshalPairs(data, parseQuery)
+	*q = values
+	return err
+}
+
+// unmarshalPairs decodes the object or the list of [name, value] pairs. The string is decoded by parseString,
+// if it is set. Not string values are kept as JSON.
+func unmarshalPairs(data []byte, parseString func(string) map[string]string) (map[string]string, error) {
+	if string(data) == "null" {
+		return nil, nil
+	}
+	var asMap map[string]StrongString
+	if err := json.Unmarshal(data, &asMap); err == nil {
+		result := make(map[string]string, len(asMap))
+		for k, v := range asMap {
+			result[k] = string(v)
+		}
+		return result, nil
+	}
+	var asPairs [][]StrongString
+	if err := json.Unmarshal(data, &asPairs); err == nil {
+		result := make(map[string]string, len(asPairs))
+		for _, pair := range asPairs {
+			if len(pair) == 2 {
+				result[string(pair[0])] = string(pair[1])
+			}
+		}
+		return result, nil
+	}
+	var asString string
+	err := json.Unmarshal(data, &asString)
+	if err != nil || parseString == nil {
+		return nil, err
+	}
+	return parseString(asString), nil
+}
+
+func parseCookies(value string) map[string]string {
+	result := make(map[string]string)
+	for _, cookie := range strings.Split(value, ";") {
+		name, cookieValue, _ := strings.Cut(strings.TrimSpace(cookie), "=")
+		if name != "" {
+			result[name] = cookieValue
+		}
+	}
+	return result
+}
+
+func parseQuery(value string) map[string]string {
+	result := make(map[string]string)
+	values, _ := url.ParseQuery(strings.TrimPrefix(value, "?"))
+	for k := range values {
+		result[k] = values.Get(k)
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	STATSD_TYPE_COUNTER      = "c"
+	STATSD_TYPE_DISTRIBUTION = "d"
+	STATSD_TYPE_SET          = "s"
+	STATSD_TYPE_GAUGE        = "g"
+)
+
+var statsdTagValueReplacer = strings.NewReplacer(
+	`\\`, `\`,
+	`\n`, "\n",
+	`\r`, "\r",
+	`\t`, "\t",
+	`\u{7c}`, "|",
+	`\u{2c}`, ",",
+)
+
+// StatsdMetric is a single line of the statsd envelope item:
+// <name>@<unit>:<value>[:<value>...]|<type>|#<tag>:<value>,...|T<timestamp>
+type StatsdMetric struct {
+	Name      string
+	Unit      string
+	Type      string
+	Values    []float64
+	SetValues []string
+	Tags      map[string]string
+	Timestamp int64
+}
+
+func ParseStatsdPayload(payload string) ([]StatsdMetric, error) {
+	result := make([]StatsdMetric, 0)
+	for _, line := range strings.Split(payload, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		metric, err := ParseStatsdLine(line)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, metric)
+	}
+	return result, nil
+}
+
+func ParseStatsdLine(line string) (StatsdMetric, error) {
+	metric := StatsdMetric{
+		Unit: "none",
+		Tags: make(map[string]string),
+	}
+	segments := strings.Split(line, "|")
+	if len(segments) < 2 {
+		return metric, fmt.Errorf("statsd line %q has no metric type", line)
+	}
+
+	nameAndValues := strings.Split(segments[0], ":")
+	if len(nameAndValues) < 2 {
+		return metric, fmt.Errorf("statsd line %q has no metric value", line)
+	}
+	metric.Name = nameAndValues[0]
+	if idx := strings.LastIndex(metric.Name, "@"); idx >= 0 {
+		metric.Unit = metric.Name[idx+1:]
+		metric.Name = metric.Name[:idx]
+	}
+	if metric.Name == "" {
+		return metric, fmt.Errorf("statsd line %q has empty metric name", line)
+	}
+
+	metric.Type = segments[1]
+	switch metric.Type {
+	case STATSD_TYPE_SET:
+		metric.SetValues = nameAndValues[1:]
+	case STATSD_TYPE_COUNTER, STATSD_TYPE_DISTRIBUTION, STATSD_TYPE_GAUGE:
+		for _, v := range nameAndValues[1:] {
+			value, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return metric, fmt.Errorf("statsd line %q has non-numeric value %q", line, v)
+			}
+			metric.Values = append(metric.Values, value)
+		}
+	default:
+		return metric, fmt.Errorf("statsd line %q has unknown metric type %q", line, metric.Type)
+	}
+
+	for _, segment := range segments[2:] {
+		switch {
+		case strings.HasPrefix(segment, "#"):
+			for _, tag := range strings.Split(segment[1:], ",") {
+				if tag == "" {
+					continue
+				}
+				key, value, _ := strings.Cut(tag, ":")
+				metric.Tags[key] = statsdTagValueReplacer.Replace(value)
+			}
+		case strings.HasPrefix(segment, "T"):
+			timestamp, err := strconv.ParseInt(segment[1:], 10, 64)
+			if err != nil {
+				return metric, fmt.Errorf("statsd line %q has invalid timestamp %q", line, segment[1:])
+			}
+			metric.Timestamp = timestamp
+		}
+	}
+	return metric, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStatsdLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected StatsdMetric
+		wantErr  bool
+	}{
+		{
+			name: "counter without unit and tags",
+			line: "button_click:1|c",
+			expected: StatsdMetric{Name: "button_click", Unit: "none", Type: STATSD_TYPE_COUNTER,
+				Values: []float64{1}, Tags: map[string]string{}},
+		},
+		{
+			name: "distribution with unit, several values, tags and timestamp",
+			line: "page_load@millisecond:12.5:30|d|#route:/home,browser:Chrome|T1700000000",
+			expected: StatsdMetric{Name: "page_load", Unit: "millisecond", Type: STATSD_TYPE_DISTRIBUTION,
+				Values: []float64{12.5, 30}, Tags: map[string]string{"route": "/home", "browser": "Chrome"}, Timestamp: 1700000000},
+		},
+		{
+			name: "set keeps the values as strings",
+			line: "users@none:alice:bob|s",
+			expected: StatsdMetric{Name: "users", Unit: "none", Type: STATSD_TYPE_SET,
+				SetValues: []string{"alice", "bob"}, Tags: map[string]string{}},
+		},
+		{
+			name: "gauge",
+			line: "queue_size:-3|g",
+			expected: StatsdMetric{Name: "queue_size", Unit: "none", Type: STATSD_TYPE_GAUGE,
+				Values: []float64{-3}, Tags: map[string]string{}},
+		},
+		{
+			name: "escaped tag values",
+			line: `requests:1|c|#path:/a\u{2c}b,note:x\u{7c}y\\z,empty:,`,
+			expected: StatsdMetric{Name: "requests", Unit: "none", Type: STATSD_TYPE_COUNTER,
+				Values: []float64{1}, Tags: map[string]string{"path": "/a,b", "note": `x|y\z`, "empty": ""}},
+		},
+		{
+			name: "unit is taken after the last @",
+			line: "user@example@second:2|d",
+			expected: StatsdMetric{Name: "user@example", Unit: "second", Type: STATSD_TYPE_DISTRIBUTION,
+				Values: []float64{2}, Tags: map[string]string{}},
+		},
+		{name: "no type", line: "button_click:1", wantErr: true},
+		{name: "no value", line: "button_click|c", wantErr: true},
+		{name: "empty name", line: "@second:1|c", wantErr: true},
+		{name: "non-numeric value", line: "button_click:one|c", wantErr: true},
+		{name: "unknown type", line: "button_click:1|h", wantErr: true},
+		{name: "invalid timestamp", line: "button_click:1|c|Tnow", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric, err := ParseStatsdLine(tt.line)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", metric)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error : %v", err)
+			}
+			if !reflect.DeepEqual(metric, tt.expected) {
+				t.Errorf("got %+v, expected %+v", metric, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseStatsdPayload(t *testing.T) {
+	metrics, err := ParseStatsdPayload("a:1|c\n\n  b@second:2|d  \n")
+	if err != nil {
+		t.Fatalf("unexpected error : %v", err)
+	}
+	if len(metrics) != 2 || metrics[0].Name != "a" || metrics[1].Name != "b" || metrics[1].Unit != "second" {
+		t.Errorf("unexpected metrics %+v", metrics)
+	}
+
+	if _, err = ParseStatsdPayload("a:1|c\nb:x|c"); err == nil {
+		t.Error("expected error for the invalid line")
+	}
+}
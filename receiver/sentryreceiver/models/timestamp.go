@@ -0,0 +1,138 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timestamp is the time of the Sentry event, span, breadcrumb or session. SDKs send it either as a number
+// of unix seconds with the fraction (e.g. 1700000000.123456) or as a string in RFC 3339 format.
+// The zero value means that the timestamp is not set.
+type Timestamp struct {
+	time time.Time
+}
+
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{time: t}
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*t = Timestamp{}
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	var err error
+	switch valTyped := value.(type) {
+	case json.Number:
+		t.time, err = parseUnixSeconds(valTyped.String())
+	case string:
+		t.time, err = parseTimestampString(valTyped)
+	default:
+		err = fmt.Errorf("timestamp must be a number or a string, got %s", data)
+	}
+	// 0 means that the timestamp is not set
+	if err == nil && t.time.Equal(time.Unix(0, 0)) {
+		t.time = time.Time{}
+	}
+	return err
+}
+
+// IsZero reports whether the timestamp is not set
+func (t Timestamp) IsZero() bool {
+	return t.time.IsZero()
+}
+
+// Time returns the time of the timestamp. The unset timestamp is the unix epoch, as 0 of the numeric timestamp.
+func (t Timestamp) Time() time.Time {
+	if t.time.IsZero() {
+		return time.Unix(0, 0)
+	}
+	return t.time
+}
+
+// Seconds returns the unix time in seconds with the fraction
+func (t Timestamp) Seconds() float64 {
+	if t.time.IsZero() {
+		return 0
+	}
+	return float64(t.time.UnixNano()) / 1e9
+}
+
+func (t Timestamp) String() string {
+	if t.time.IsZero() {
+		return ""
+	}
+	return t.time.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTimestampString(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if timestamp, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return timestamp, nil
+	}
+	// some SDKs send the unix time as a string
+	if timestamp, err := parseUnixSeconds(value); err == nil {
+		return timestamp, nil
+	}
+	return time.Time{}, fmt.Errorf("timestamp %q is neither RFC 3339 time nor unix time", value)
+}
+
+// parseUnixSeconds parses the decimal number of unix seconds. The integer and the fraction parts are parsed separately,
+// because float64 keeps only microseconds of the current unix time.
+func parseUnixSeconds(value string) (time.Time, error) {
+	if strings.ContainsAny(value, "eE") {
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		sec, dec := math.Modf(seconds)
+		return time.Unix(int64(sec), int64(dec*1e9)), nil
+	}
+	intPart, fracPart, _ := strings.Cut(value, ".")
+	negative := strings.HasPrefix(intPart, "-")
+	sec, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var nsec int64
+	if fracPart != "" {
+		if len(fracPart) > 9 {
+			fracPart = fracPart[:9]
+		}
+		fracPart += strings.Repeat("0", 9-len(fracPart))
+		if nsec, err = strconv.ParseInt(fracPart, 10, 64); err != nil || nsec < 0 {
+			return time.Time{}, fmt.Errorf("invalid fraction of timestamp %q", value)
+		}
+		if negative {
+			nsec = -nsec
+		}
+	}
+	return time.Unix(sec, nsec), nil
+}
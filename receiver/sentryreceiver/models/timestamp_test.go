@@ -0,0 +1,93 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestampUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected time.Time
+		isZero   bool
+		wantErr  bool
+	}{
+		{name: "integer seconds", json: `1700000000`, expected: time.Unix(1700000000, 0)},
+		{name: "fractional seconds keep nanoseconds", json: `1700000000.123456789`, expected: time.Unix(1700000000, 123456789)},
+		{name: "short fraction", json: `1700000000.5`, expected: time.Unix(1700000000, 500000000)},
+		{name: "fraction longer than nanoseconds is truncated", json: `1700000000.1234567891`, expected: time.Unix(1700000000, 123456789)},
+		{name: "exponent", json: `1.7e9`, expected: time.Unix(1700000000, 0)},
+		{name: "negative seconds", json: `-1.5`, expected: time.Unix(-1, -500000000)},
+		{name: "RFC 3339 string", json: `"2023-11-14T22:13:20Z"`, expected: time.Unix(1700000000, 0)},
+		{name: "RFC 3339 string with offset and fraction", json: `"2023-11-15T00:13:20.25+02:00"`, expected: time.Unix(1700000000, 250000000)},
+		{name: "unix seconds as string", json: `"1700000000.25"`, expected: time.Unix(1700000000, 250000000)},
+		{name: "null is not set", json: `null`, isZero: true},
+		{name: "zero is not set", json: `0`, isZero: true},
+		{name: "empty string is not set", json: `""`, isZero: true},
+		{name: "not a time", json: `"yesterday"`, wantErr: true},
+		{name: "boolean", json: `true`, wantErr: true},
+		{name: "object", json: `{"seconds":1}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var timestamp Timestamp
+			err := json.Unmarshal([]byte(tt.json), &timestamp)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", timestamp)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error : %v", err)
+			}
+			if timestamp.IsZero() != tt.isZero {
+				t.Fatalf("IsZero() = %v, expected %v", timestamp.IsZero(), tt.isZero)
+			}
+			if tt.isZero {
+				if !timestamp.Time().Equal(time.Unix(0, 0)) || timestamp.Seconds() != 0 || timestamp.String() != "" {
+					t.Errorf("unset timestamp must be the unix epoch, got %v, %v, %q", timestamp.Time(), timestamp.Seconds(), timestamp.String())
+				}
+				return
+			}
+			if !timestamp.Time().Equal(tt.expected) {
+				t.Errorf("Time() = %v, expected %v", timestamp.Time(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestTimestampInStruct(t *testing.T) {
+	var event struct {
+		Timestamp      Timestamp `json:"timestamp"`
+		StartTimestamp Timestamp `json:"start_timestamp"`
+	}
+	if err := json.Unmarshal([]byte(`{"timestamp":"2023-11-14T22:13:20.5Z"}`), &event); err != nil {
+		t.Fatalf("unexpected error : %v", err)
+	}
+	if !event.StartTimestamp.IsZero() {
+		t.Errorf("missing timestamp must not be set, got %v", event.StartTimestamp)
+	}
+	if event.Timestamp.Seconds() != 1700000000.5 {
+		t.Errorf("Seconds() = %v, expected 1700000000.5", event.Timestamp.Seconds())
+	}
+	if event.Timestamp.String() != "2023-11-14T22:13:20.5Z" {
+		t.Errorf("String() = %q, expected 2023-11-14T22:13:20.5Z", event.Timestamp.String())
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// Actions for the requests, which origin is not allowed for the project
+const (
+	originActionReject = "reject"
+	originActionTag    = "tag"
+)
+
+// Types of the requests, which are checked by allowed-origins
+const (
+	originRequestEnvelope  = "envelope"
+	originRequestPreflight = "preflight"
+)
+
+// corsAllowedHeaders are the request headers of the browser SDKs, which are allowed by the preflight response,
+// if the preflight request doesn't list the headers
+const corsAllowedHeaders = "x-sentry-auth, x-requested-with, origin, referer, accept, content-type, content-encoding, authorization, baggage, sentry-trace"
+
+// originPattern is one value of allowed-origins:
+//   - "*" allows all origins
+//   - "~<regexp>" matches the whole origin (e.g. "~https://shop-[0-9]+\.example\.com")
+//   - "https://*.example.com" or "*.example.com" matches any subdomain of example.com, but not example.com itself
+//   - "https://shop.example.com" matches the origin exactly, "shop.example.com" matches the host with any scheme
+//
+// The port of the origin is checked only if the pattern contains the port.
+type originPattern struct {
+	any      bool
+	regex    *regexp.Regexp
+	scheme   string
+	hostname string
+	port     string
+	wildcard bool
+}
+
+func newOriginPattern(pattern string) (originPattern, error) {
+	if pattern == "*" {
+		return originPattern{any: true}, nil
+	}
+	if strings.HasPrefix(pattern, "~") {
+		regex, err := regexp.Compile("^(?:" + pattern[1:] + ")$")
+		if err != nil {
+			return originPattern{}, err
+		}
+		return originPattern{regex: regex}, nil
+	}
+	op := originPattern{}
+	host := strings.TrimSuffix(strings.ToLower(pattern), "/")
+	if scheme, rest, found := strings.Cut(host, "://"); found {
+		op.scheme, host = scheme, rest
+	}
+	if strings.HasPrefix(host, "*.") {
+		op.wildcard = true
+		host = host[2:]
+	}
+	u, err := url.Parse("origin://" + host)
+	if err != nil || u.Hostname() == "" || u.Host != host || strings.Contains(host, "*") {
+		return originPattern{}, fmt.Errorf("%v is not a valid origin", pattern)
+	}
+	op.hostname, op.port = u.Hostname(), u.Port()
+	return op, nil
+}
+
+func (op originPattern) matches(origin string) bool {
+	switch {
+	case op.any:
+		return true
+	case op.regex != nil:
+		return op.regex.MatchString(origin)
+	}
+	u, err := url.Parse(strings.ToLower(origin))
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	if op.scheme != "" && op.scheme != u.Scheme {
+		return false
+	}
+	if op.port != "" && op.port != u.Port() {
+		return false
+	}
+	if op.wildcard {
+		return strings.HasSuffix(u.Hostname(), "."+op.hostname)
+	}
+	return u.Hostname() == op.hostname
+}
+
+// getRequestOrigin returns the Origin header or, if it is not set, the origin of the Referer header
+func getRequestOrigin(r *http.Request) string {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		return origin
+	}
+	referer, err := url.Parse(r.Header.Get("Referer"))
+	if err != nil || referer.Scheme == "" || referer.Host == "" {
+		return ""
+	}
+	return referer.Scheme + "://" + referer.Host
+}
+
+// checkOrigin checks the origin of the browser request against allowed-origins of the project.
+// The requests without Origin and Referer headers (e.g. from the backend SDKs) are allowed, unless require-origin is set.
+func checkOrigin(r *http.Request, settings *projectSettings) (string, bool) {
+	origin := getRequestOrigin(r)
+	if settings == nil || len(settings.originPatterns) == 0 {
+		return origin, true
+	}
+	if origin == "" {
+		return origin, !settings.RequireOrigin
+	}
+	for _, pattern := range settings.originPatterns {
+		if pattern.matches(origin) {
+			return origin, true
+		}
+	}
+	return origin, false
+}
+
+func (ps *projectSettings) originMismatchAction() string {
+	if ps.OriginMismatchAction == "" {
+		return originActionReject
+	}
+	return ps.OriginMismatchAction
+}
+
+// handlePreflight answers the CORS preflight request. If the project has allowed-origins, only the allowed origins
+// get CORS headers, so that the browser doesn't send envelopes from the other origins.
+func (sr *sentrytraceReceiver) handlePreflight(w http.ResponseWriter, r *http.Request) {
+	projectId := getProjectId(r, &models.EnvelopEventParseResult{})
+	project := sr.projects.get(projectId)
+	origin, allowed := checkOrigin(r, project)
+	allowedOrigin := "*"
+	if project != nil && len(project.originPatterns) > 0 {
+		if !allowed {
+			action := project.originMismatchAction()
+			sr.telemetry.recordOriginRejection(r.Context(), projectId, originRequestPreflight, action)
+			if action == originActionReject {
+				writeErrorResponse(w, http.StatusForbidden, fmt.Sprintf("origin %v is not allowed", origin))
+				return
+			}
+		}
+		allowedOrigin = r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+	}
+	allowedHeaders := r.Header.Get("Access-Control-Request-Headers")
+	if allowedHeaders == "" {
+		allowedHeaders = corsAllowedHeaders
+	}
+	w.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+	w.Header().Set("Access-Control-Max-Age", "3600")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// allowOrigin checks the origin of the envelope request. It returns false, if the request is rejected.
+// The allowed browser requests get CORS headers, so that the browser SDK can read the response.
+func (sr *sentrytraceReceiver) allowOrigin(ctx context.Context, w http.ResponseWriter, r *http.Request, projectId string, project *projectSettings) bool {
+	origin, allowed := checkOrigin(r, project)
+	if allowed {
+		if project != nil && len(project.originPatterns) > 0 && r.Header.Get("Origin") != "" {
+			w.Header().Set("Access-Control-Allow-Origin", r.Header.Get("Origin"))
+			w.Header().Add("Vary", "Origin")
+		}
+		return true
+	}
+	action := project.originMismatchAction()
+	sr.telemetry.recordOriginRejection(ctx, projectId, originRequestEnvelope, action)
+	if action == originActionReject {
+		sr.logger.Sugar().Debugf("Envelope of project %v is rejected : origin %v is not allowed", projectId, origin)
+		writeErrorResponse(w, http.StatusForbidden, fmt.Sprintf("origin %v is not allowed", origin))
+		return false
+	}
+	return true
+}
+
+// putOriginAttributes marks the spans of the envelope, which origin is not allowed, if origin-mismatch-action is "tag"
+func putOriginAttributes(attrs pcommon.Map, r *http.Request) {
+	if origin, allowed := checkOrigin(r, getProjectSettings(r)); !allowed {
+		putNotEmptyStr(attrs, "sentry.request_origin", origin)
+		attrs.PutBool("sentry.request_origin.allowed", false)
+	}
+}
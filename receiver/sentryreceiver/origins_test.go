@@ -0,0 +1,108 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOriginPatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		origin  string
+		matches bool
+	}{
+		{pattern: "*", origin: "https://anything.example.org", matches: true},
+
+		{pattern: "https://shop.example.com", origin: "https://shop.example.com", matches: true},
+		{pattern: "https://shop.example.com", origin: "https://SHOP.example.com", matches: true},
+		{pattern: "https://shop.example.com/", origin: "https://shop.example.com", matches: true},
+		{pattern: "https://shop.example.com", origin: "http://shop.example.com", matches: false},
+		{pattern: "https://shop.example.com", origin: "https://shop.example.com:8443", matches: true},
+		{pattern: "https://shop.example.com", origin: "https://shop.example.com.evil.org", matches: false},
+		{pattern: "shop.example.com", origin: "http://shop.example.com", matches: true},
+		{pattern: "shop.example.com", origin: "https://shop.example.com", matches: true},
+
+		{pattern: "https://shop.example.com:8443", origin: "https://shop.example.com:8443", matches: true},
+		{pattern: "https://shop.example.com:8443", origin: "https://shop.example.com", matches: false},
+		{pattern: "https://shop.example.com:8443", origin: "https://shop.example.com:9443", matches: false},
+
+		{pattern: "https://*.example.com", origin: "https://shop.example.com", matches: true},
+		{pattern: "https://*.example.com", origin: "https://a.b.example.com:8443", matches: true},
+		{pattern: "https://*.example.com", origin: "https://example.com", matches: false},
+		{pattern: "https://*.example.com", origin: "https://evilexample.com", matches: false},
+		{pattern: "https://*.example.com", origin: "http://shop.example.com", matches: false},
+		{pattern: "*.example.com", origin: "http://shop.example.com", matches: true},
+
+		{pattern: `~https://shop-[0-9]+\.example\.net`, origin: "https://shop-12.example.net", matches: true},
+		{pattern: `~https://shop-[0-9]+\.example\.net`, origin: "https://shop-12.example.net.evil.org", matches: false},
+		{pattern: `~https://shop-[0-9]+\.example\.net`, origin: "https://evil.org/https://shop-1.example.net", matches: false},
+		{pattern: `~https://a\.example\.com|https://b\.example\.com`, origin: "https://b.example.com", matches: true},
+		{pattern: `~https://a\.example\.com|https://b\.example\.com`, origin: "https://b.example.com.evil.org", matches: false},
+
+		{pattern: "https://shop.example.com", origin: "null", matches: false},
+		{pattern: "https://shop.example.com", origin: "", matches: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.origin, func(t *testing.T) {
+			pattern, err := newOriginPattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("unexpected error : %v", err)
+			}
+			if matches := pattern.matches(tt.origin); matches != tt.matches {
+				t.Errorf("matches(%q) = %v, expected %v", tt.origin, matches, tt.matches)
+			}
+		})
+	}
+}
+
+func TestNewOriginPatternInvalid(t *testing.T) {
+	for _, pattern := range []string{"~(", "https://", "https://shop.*.example.com", "*.", "https://shop.example.com/path"} {
+		t.Run(pattern, func(t *testing.T) {
+			if _, err := newOriginPattern(pattern); err == nil {
+				t.Errorf("expected error for %q", pattern)
+			}
+		})
+	}
+}
+
+func TestGetRequestOrigin(t *testing.T) {
+	tests := []struct {
+		name     string
+		origin   string
+		referer  string
+		expected string
+	}{
+		{name: "origin header", origin: "https://shop.example.com", referer: "https://other.example.com/page", expected: "https://shop.example.com"},
+		{name: "origin of the referer", referer: "https://shop.example.com:8443/cart?id=1", expected: "https://shop.example.com:8443"},
+		{name: "relative referer", referer: "/cart", expected: ""},
+		{name: "no headers", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/1/envelope/", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.referer != "" {
+				r.Header.Set("Referer", tt.referer)
+			}
+			if origin := getRequestOrigin(r); origin != tt.expected {
+				t.Errorf("got %q, expected %q", origin, tt.expected)
+			}
+		})
+	}
+}
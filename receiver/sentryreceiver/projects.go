@@ -0,0 +1,244 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.yaml.in/yaml/v3"
+)
+
+// projectSettings contains the settings of one Sentry project from the projects file.
+// The list settings override the settings of the receiver configuration, if they are set.
+type projectSettings struct {
+	ServiceName                    string   `yaml:"service-name"`
+	AllowedOrigins                 []string `yaml:"allowed-origins"`
+	RequireOrigin                  bool     `yaml:"require-origin"`
+	OriginMismatchAction           string   `yaml:"origin-mismatch-action"`
+	HttpQueryParamValuesToAttrs    []string `yaml:"http-query-param-values-to-attrs"`
+	HttpQueryParamExistenceToAttrs []string `yaml:"http-query-param-existence-to-attrs"`
+	ContextSpanAttributesList      []string `yaml:"context-span-attributes-list"`
+	SampleRate                     *float64 `yaml:"sample-rate"`
+
+	// compiled allowed-origins
+	originPatterns []originPattern
+}
+
+// projectsFile is the content of the projects file. The file is YAML or JSON, the projects are keyed by Sentry project id.
+type projectsFile struct {
+	Projects map[string]*projectSettings `yaml:"projects"`
+}
+
+func (pf *projectsFile) validate() error {
+	for projectId, settings := range pf.Projects {
+		if settings == nil {
+			return fmt.Errorf("project %v has no settings", projectId)
+		}
+		if settings.SampleRate != nil && (*settings.SampleRate < 0 || *settings.SampleRate > 1) {
+			return fmt.Errorf("sample-rate of project %v must be between 0 and 1 (actual value is %v)", projectId, *settings.SampleRate)
+		}
+		settings.originPatterns = make([]originPattern, 0, len(settings.AllowedOrigins))
+		for _, origin := range settings.AllowedOrigins {
+			if origin == "" {
+				return fmt.Errorf("allowed-origins of project %v can not contain empty values", projectId)
+			}
+			pattern, err := newOriginPattern(origin)
+			if err != nil {
+				return fmt.Errorf("allowed-origins of project %v contains invalid value %v : %+v", projectId, origin, err)
+			}
+			settings.originPatterns = append(settings.originPatterns, pattern)
+		}
+		switch settings.OriginMismatchAction {
+		case "", originActionReject, originActionTag:
+		default:
+			return fmt.Errorf("origin-mismatch-action of project %v must be %v or %v (actual value is %v)", projectId, originActionReject, originActionTag, settings.OriginMismatchAction)
+		}
+	}
+	return nil
+}
+
+func parseProjectsFile(content []byte) (*projectsFile, error) {
+	pf := &projectsFile{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(pf); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if err := pf.validate(); err != nil {
+		return nil, err
+	}
+	return pf, nil
+}
+
+// projectsRegistry keeps the last valid version of the projects file. The version is replaced atomically,
+// so that the requests, which are processed during the reload, see either the old or the new settings.
+type projectsRegistry struct {
+	path     string
+	projects atomic.Pointer[projectsFile]
+	// content of the last read file, the file is parsed again only if its content is changed
+	content []byte
+}
+
+func newProjectsRegistry(path string) *projectsRegistry {
+	return &projectsRegistry{path: path}
+}
+
+// reload reads the projects file and replaces the projects, if the file is changed and valid.
+// It returns true, if the projects are replaced.
+func (pr *projectsRegistry) reload() (bool, error) {
+	content, err := os.ReadFile(pr.path)
+	if err != nil {
+		return false, err
+	}
+	if pr.projects.Load() != nil && bytes.Equal(content, pr.content) {
+		return false, nil
+	}
+	pr.content = content
+	pf, err := parseProjectsFile(content)
+	if err != nil {
+		return false, err
+	}
+	pr.projects.Store(pf)
+	return true, nil
+}
+
+func (pr *projectsRegistry) get(projectId string) *projectSettings {
+	if pr == nil || projectId == "" {
+		return nil
+	}
+	pf := pr.projects.Load()
+	if pf == nil {
+		return nil
+	}
+	return pf.Projects[projectId]
+}
+
+func (sr *sentrytraceReceiver) loadProjects() error {
+	sr.projects = newProjectsRegistry(sr.config.ProjectsCfg.File)
+	if _, err := sr.projects.reload(); err != nil {
+		return fmt.Errorf("projects file %v can not be loaded : %w", sr.config.ProjectsCfg.File, err)
+	}
+	sr.logger.Sugar().Infof("SentryReceiver : Projects file %v is loaded", sr.config.ProjectsCfg.File)
+	return nil
+}
+
+func (sr *sentrytraceReceiver) watchProjects(ctx context.Context) {
+	// the duration is checked by Config.Validate
+	interval, _ := time.ParseDuration(sr.config.ProjectsCfg.ReloadInterval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := sr.projects.reload()
+			if err != nil {
+				sr.logger.Sugar().Errorf("SentryReceiver : Error reloading projects file %v, the previous version is used : %+v", sr.config.ProjectsCfg.File, err)
+				sr.telemetry.recordProjectsReload(ctx, false)
+				continue
+			}
+			if reloaded {
+				sr.logger.Sugar().Infof("SentryReceiver : Projects file %v is reloaded", sr.config.ProjectsCfg.File)
+				sr.telemetry.recordProjectsReload(ctx, true)
+			}
+		}
+	}
+}
+
+type projectSettingsKey struct{}
+
+// withProjectSettings puts the settings of the envelope project to the request context,
+// so that all items of the envelope are processed with the same version of the settings
+func withProjectSettings(r *http.Request, settings *projectSettings) *http.Request {
+	if settings == nil {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), projectSettingsKey{}, settings))
+}
+
+func getProjectSettings(r *http.Request) *projectSettings {
+	settings, _ := r.Context().Value(projectSettingsKey{}).(*projectSettings)
+	return settings
+}
+
+func (sr *sentrytraceReceiver) httpQueryParamValuesToAttrs(r *http.Request) []string {
+	if settings := getProjectSettings(r); settings != nil && settings.HttpQueryParamValuesToAttrs != nil {
+		return settings.HttpQueryParamValuesToAttrs
+	}
+	return sr.config.HttpQueryParamValuesToAttrs
+}
+
+func (sr *sentrytraceReceiver) httpQueryParamExistenceToAttrs(r *http.Request) []string {
+	if settings := getProjectSettings(r); settings != nil && settings.HttpQueryParamExistenceToAttrs != nil {
+		return settings.HttpQueryParamExistenceToAttrs
+	}
+	return sr.config.HttpQueryParamExistenceToAttrs
+}
+
+func (sr *sentrytraceReceiver) contextSpanAttributesList(r *http.Request) []string {
+	if settings := getProjectSettings(r); settings != nil && settings.ContextSpanAttributesList != nil {
+		return settings.ContextSpanAttributesList
+	}
+	return sr.config.ContextSpanAttributesList
+}
+
+// isEnvelopeSampled decides whether the envelope is kept according to sample-rate of the project.
+// The decision is made by the trace id, so that all envelopes of one trace are either kept or dropped.
+func isEnvelopeSampled(envlp *models.EnvelopEventParseResult, settings *projectSettings) bool {
+	if settings == nil || settings.SampleRate == nil || *settings.SampleRate >= 1 {
+		return true
+	}
+	traceId := envlp.EnvelopEventHeader.Trace.TraceId
+	if traceId == "" && len(envlp.Events) > 0 {
+		traceId = envlp.Events[0].Contexts.Trace.TraceID
+	}
+	if data, err := hex.DecodeString(traceId); err == nil && len(data) == 16 {
+		return float64(binary.BigEndian.Uint64(data[8:])) < *settings.SampleRate*math.MaxUint64
+	}
+	return rand.Float64() < *settings.SampleRate
+}
+
+// recordSkippedItems counts the items of the envelope, which is dropped by sampling or deduplication
+func (sr *sentrytraceReceiver) recordSkippedItems(ctx context.Context, envlp *models.EnvelopEventParseResult, reason string) {
+	itemTypes := map[string]int{
+		"session":  len(envlp.SessionEvents),
+		"span":     len(envlp.Spans),
+		"feedback": len(envlp.Feedbacks),
+	}
+	if envlp.EnvelopType == models.ENVELOP_TYPE_TRANSACTION {
+		itemTypes["transaction"] = len(envlp.Events)
+	} else {
+		itemTypes["event"] = len(envlp.Events)
+	}
+	for itemType, count := range itemTypes {
+		for i := 0; i < count; i++ {
+			sr.telemetry.recordSkippedItem(ctx, itemType, reason)
+		}
+	}
+}
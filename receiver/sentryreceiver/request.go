@@ -0,0 +1,94 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// putRequestAttributes adds the method and the allowed headers, cookies, environment variables and body
+// of the request interface of the event to the span according to request settings.
+// The values of the sensitive keys are scrubbed and all values are cut to request.max-value-length.
+func (sr *sentrytraceReceiver) putRequestAttributes(attrs pcommon.Map, request models.EventRequest) {
+	cfg := sr.config.RequestCfg
+	putNotEmptyStr(attrs, "http.request.method", strings.ToUpper(request.Method))
+	for _, name := range cfg.Headers {
+		if value := request.Headers.Get(name); value != "" {
+			attrs.PutStr("http.request.header."+strings.ToLower(name), sr.requestValue(name, value))
+		}
+	}
+	for _, name := range cfg.Cookies {
+		if value, ok := request.Cookies[name]; ok {
+			attrs.PutStr("http.request.cookie."+name, sr.requestValue(name, value))
+		}
+	}
+	for _, name := range cfg.Env {
+		if value, ok := request.Env[name]; ok {
+			attrs.PutStr("http.request.env."+name, sr.requestValue(name, string(value)))
+		}
+	}
+	if cfg.Data && request.Data != nil {
+		putNotEmptyStr(attrs, "http.request.data", truncateString(sr.requestData(request.Data), cfg.MaxValueLength))
+	}
+}
+
+// requestData returns the scrubbed request body. The body can be sent as a JSON value, a JSON string or
+// a form-encoded string. The string body, which can not be parsed, is replaced, because it can not be scrubbed.
+func (sr *sentrytraceReceiver) requestData(data interface{}) string {
+	if dataStr, ok := data.(string); ok {
+		if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
+			return sr.scrubFormData(dataStr)
+		}
+		if dataStr, ok = data.(string); ok {
+			return sr.scrubFormData(dataStr)
+		}
+	}
+	dataJson, _ := json.Marshal(sr.scrubJSON(data))
+	return string(dataJson)
+}
+
+func (sr *sentrytraceReceiver) scrubFormData(data string) string {
+	form, err := url.ParseQuery(data)
+	if err != nil || !strings.Contains(data, "=") {
+		return sr.config.ScrubbingCfg.Replacement
+	}
+	for key, values := range form {
+		for i := range values {
+			values[i] = sr.scrubValue(key, values[i])
+		}
+	}
+	return form.Encode()
+}
+
+func (sr *sentrytraceReceiver) requestValue(name string, value string) string {
+	return truncateString(sr.scrubValue(name, value), sr.config.RequestCfg.MaxValueLength)
+}
+
+// getRequestQuery returns the query parameters of the URL and query_string of the request interface.
+// The parameters of the URL have priority.
+func getRequestQuery(urlParsed *url.URL, request models.EventRequest) url.Values {
+	query := urlParsed.Query()
+	for k, v := range request.QueryString {
+		if !query.Has(k) {
+			query.Set(k, v)
+		}
+	}
+	return query
+}
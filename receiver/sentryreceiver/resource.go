@@ -0,0 +1,139 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	conventions "go.opentelemetry.io/collector/semconv/v1.9.0"
+)
+
+// Sentry platforms and the corresponding telemetry.sdk.language values.
+// Other platforms are set to telemetry.sdk.language as is.
+var platformLanguages = map[string]string{
+	"javascript": conventions.AttributeTelemetrySDKLanguageWebjs,
+	"node":       conventions.AttributeTelemetrySDKLanguageNodejs,
+	"cocoa":      conventions.AttributeTelemetrySDKLanguageSwift,
+	"csharp":     conventions.AttributeTelemetrySDKLanguageDotnet,
+	"native":     conventions.AttributeTelemetrySDKLanguageCPP,
+	"elixir":     conventions.AttributeTelemetrySDKLanguageErlang,
+}
+
+// resourceKey identifies the resource of the spans. The items of one envelope
+// with different releases or environments are put to different ResourceSpans.
+type resourceKey struct {
+	release     string
+	environment string
+}
+
+// resourceSpansBuilder creates the ResourceSpans of the envelope on demand
+type resourceSpansBuilder struct {
+	sr     *sentrytraceReceiver
+	traces ptrace.Traces
+	envlp  *models.EnvelopEventParseResult
+	r      *http.Request
+	scopes map[resourceKey]ptrace.ScopeSpans
+}
+
+func (sr *sentrytraceReceiver) newResourceSpansBuilder(traces ptrace.Traces, envlp *models.EnvelopEventParseResult, r *http.Request) *resourceSpansBuilder {
+	return &resourceSpansBuilder{
+		sr:     sr,
+		traces: traces,
+		envlp:  envlp,
+		r:      r,
+		scopes: make(map[resourceKey]ptrace.ScopeSpans),
+	}
+}
+
+// scopeSpans returns the ScopeSpans for the item with the given release and environment.
+// The release and environment of the envelope header are used, if the item doesn't have them.
+// The platform is used for telemetry.sdk.language of the new resource only.
+func (b *resourceSpansBuilder) scopeSpans(release string, environment string, platform string) ptrace.ScopeSpans {
+	key := b.key(release, environment)
+	scopeSpans, ok := b.scopes[key]
+	if !ok {
+		resourceSpans := b.traces.ResourceSpans().AppendEmpty()
+		resource := resourceSpans.Resource()
+		b.sr.fillResource(&resource, b.envlp, b.r, key, platform)
+		scopeSpans = resourceSpans.ScopeSpans().AppendEmpty()
+		b.scopes[key] = scopeSpans
+	}
+	return scopeSpans
+}
+
+// key returns the resource key of the item with the release and environment of the envelope header as the fallback
+func (b *resourceSpansBuilder) key(release string, environment string) resourceKey {
+	if release == "" {
+		release = b.envlp.EnvelopEventHeader.Trace.Release
+	}
+	if environment == "" {
+		environment = b.envlp.EnvelopEventHeader.Trace.Environment
+	}
+	return resourceKey{release: release, environment: environment}
+}
+
+func (sr *sentrytraceReceiver) fillResource(resource *pcommon.Resource, envlp *models.EnvelopEventParseResult, r *http.Request, key resourceKey, platform string) {
+	attrs := resource.Attributes()
+	sdkInfo := envlp.EnvelopEventHeader.SdkInfo
+	attrs.PutStr(conventions.AttributeTelemetrySDKName, sdkInfo.Name)
+	if sdkInfo.Version != "" {
+		attrs.PutStr(conventions.AttributeTelemetrySDKVersion, sdkInfo.Version)
+	}
+	if language := getSdkLanguage(platform, sdkInfo.Name); language != "" {
+		attrs.PutStr(conventions.AttributeTelemetrySDKLanguage, language)
+	}
+	attrs.PutStr(conventions.AttributeServiceName, sr.GetServiceName(r))
+	if namespace := sr.getServiceNamespace(r); namespace != "" {
+		attrs.PutStr(conventions.AttributeServiceNamespace, namespace)
+	}
+	if key.release != "" {
+		attrs.PutStr(conventions.AttributeServiceVersion, key.release)
+	}
+	if key.environment != "" {
+		attrs.PutStr(conventions.AttributeDeploymentEnvironment, key.environment)
+	}
+	attrs.PutStr("trace.source.type", "sentry")
+	if envlp.Tenant != "" {
+		attrs.PutStr(tenantAttributeName, envlp.Tenant)
+	}
+}
+
+// getSdkLanguage evaluates telemetry.sdk.language from the platform of the event or,
+// if the platform is unknown, from the SDK name, e.g. "sentry.javascript.browser"
+func getSdkLanguage(platform string, sdkName string) string {
+	if platform == "" {
+		sdkNameElements := strings.Split(sdkName, ".")
+		if len(sdkNameElements) < 2 {
+			return ""
+		}
+		platform = sdkNameElements[1]
+	}
+	if language, ok := platformLanguages[platform]; ok {
+		return language
+	}
+	return platform
+}
+
+func (sr *sentrytraceReceiver) getServiceNamespace(r *http.Request) string {
+	namespace := r.Header.Get("x-service-namespace")
+	if namespace != "" {
+		return namespace
+	}
+	return sr.config.ServiceNamespace
+}
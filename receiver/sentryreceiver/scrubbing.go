@@ -0,0 +1,73 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"strings"
+)
+
+// scrubEmail replaces the local part of the email, so that only the domain is kept
+func (sr *sentrytraceReceiver) scrubEmail(email string) string {
+	if !sr.config.ScrubbingCfg.MaskEmails || email == "" {
+		return email
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return sr.config.ScrubbingCfg.Replacement
+	}
+	return sr.config.ScrubbingCfg.Replacement + email[at:]
+}
+
+// isSensitiveKey checks whether the name of the header, cookie or field contains any of scrubbing.sensitive-keys
+func (sr *sentrytraceReceiver) isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitiveKey := range sr.config.ScrubbingCfg.SensitiveKeys {
+		if strings.Contains(key, strings.ToLower(sensitiveKey)) {
+			return true
+		}
+	}
+	return false
+}
+
+// scrubValue replaces the value of the sensitive key
+func (sr *sentrytraceReceiver) scrubValue(key string, value string) string {
+	if value != "" && sr.isSensitiveKey(key) {
+		return sr.config.ScrubbingCfg.Replacement
+	}
+	return value
+}
+
+// scrubJSON replaces the values of the sensitive keys in the nested objects of the JSON value
+func (sr *sentrytraceReceiver) scrubJSON(value interface{}) interface{} {
+	switch valTyped := value.(type) {
+	case []interface{}:
+		result := make([]interface{}, len(valTyped))
+		for i, item := range valTyped {
+			result[i] = sr.scrubJSON(item)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(valTyped))
+		for k, v := range valTyped {
+			if v != nil && sr.isSensitiveKey(k) {
+				result[k] = sr.config.ScrubbingCfg.Replacement
+			} else {
+				result[k] = sr.scrubJSON(v)
+			}
+		}
+		return result
+	}
+	return value
+}
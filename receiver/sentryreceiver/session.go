@@ -0,0 +1,232 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// Final statuses of the Sentry session
+var sessionEndStatuses = map[string]bool{
+	"exited":   true,
+	"crashed":  true,
+	"abnormal": true,
+	"errored":  true,
+}
+
+// sessionState is the state of the session between the init update and the final update
+type sessionState struct {
+	sid         string
+	started     time.Time
+	lastUpdate  time.Time
+	lastSeen    time.Time
+	status      string
+	errors      int
+	duration    *float64
+	timedOut    bool
+	clockSkew   time.Duration // the correction of the last update, started and lastUpdate are already corrected
+	resource    pcommon.Resource
+	name        string
+	serviceName string
+}
+
+// outcome returns the result of the ended session: healthy, errored, crashed or abnormal
+func (s *sessionState) outcome() string {
+	switch {
+	case s.status == "crashed" || s.status == "abnormal":
+		return s.status
+	case s.status == "errored" || s.errors > 0:
+		return "errored"
+	}
+	return "healthy"
+}
+
+// sessionKey identifies the session. The sid is generated by the client,
+// so the sessions of different projects and tenants are kept apart.
+type sessionKey struct {
+	projectId string
+	tenant    string
+	sid       string
+}
+
+// sessionTracker keeps the state of the active sessions. The session is removed when it ends
+// or when it doesn't get updates longer than the timeout.
+type sessionTracker struct {
+	sync.Mutex
+	timeout     time.Duration
+	maxSessions int
+	sessions    map[sessionKey]*sessionState
+}
+
+func newSessionTracker(timeout time.Duration, maxSessions int) *sessionTracker {
+	return &sessionTracker{
+		timeout:     timeout,
+		maxSessions: maxSessions,
+		sessions:    make(map[sessionKey]*sessionState),
+	}
+}
+
+// update applies the session update to the state. It returns the state and true, if the session is ended.
+// nil state is returned, if the session can not be tracked, because the limit of the sessions is reached.
+// newState is called for the first update of the session. The timestamps of the update are corrected by the clock skew
+// of its envelope, because the updates of the same session can be sent with different skews.
+func (st *sessionTracker) update(key sessionKey, event models.SessionEvent, clockSkew time.Duration, now time.Time, newState func() *sessionState) (*sessionState, bool) {
+	st.Lock()
+	defer st.Unlock()
+	state, ok := st.sessions[key]
+	if !ok {
+		if len(st.sessions) >= st.maxSessions {
+			return nil, false
+		}
+		state = newState()
+		st.sessions[key] = state
+	}
+	state.clockSkew = clockSkew
+	if started := event.Started.Time().Add(clockSkew); !event.Started.IsZero() && (state.started.IsZero() || started.Before(state.started)) {
+		state.started = started
+	}
+	if timestamp := event.Timestamp.Time().Add(clockSkew); !event.Timestamp.IsZero() && timestamp.After(state.lastUpdate) {
+		state.lastUpdate = timestamp
+	}
+	if state.started.IsZero() {
+		state.started = state.lastUpdate
+	}
+	if event.Status != "" {
+		state.status = event.Status
+	}
+	if event.Errors > state.errors {
+		state.errors = event.Errors
+	}
+	if event.Duration != nil {
+		state.duration = event.Duration
+	}
+	state.lastSeen = now
+	if sessionEndStatuses[state.status] {
+		delete(st.sessions, key)
+		return state, true
+	}
+	return state, false
+}
+
+// expired removes the sessions, which didn't get updates longer than the timeout.
+// Such sessions are considered as exited, as Sentry does for the sessions without the final update.
+func (st *sessionTracker) expired(now time.Time) []*sessionState {
+	st.Lock()
+	defer st.Unlock()
+	result := make([]*sessionState, 0)
+	for key, state := range st.sessions {
+		if now.Sub(state.lastSeen) < st.timeout {
+			continue
+		}
+		delete(st.sessions, key)
+		if !sessionEndStatuses[state.status] {
+			state.status = "exited"
+		}
+		state.timedOut = true
+		result = append(result, state)
+	}
+	return result
+}
+
+// trackSession updates the lifecycle of the session and adds the span of the session, if the session is ended.
+// It returns false, if the session can not be tracked.
+func (sr *sentrytraceReceiver) trackSession(rsb *resourceSpansBuilder, envlp *models.EnvelopEventParseResult, r *http.Request, event models.SessionEvent) bool {
+	if event.Sid == "" {
+		return false
+	}
+	key := sessionKey{projectId: getProjectId(r, envlp), tenant: envlp.Tenant, sid: event.Sid}
+	state, ended := sr.sessionTracker.update(key, event, envlp.ClockSkew, time.Now(), func() *sessionState {
+		resource := pcommon.NewResource()
+		sr.fillResource(&resource, envlp, r, rsb.key(event.Attrs.Release, event.Attrs.Environment), "")
+		return &sessionState{
+			sid:         event.Sid,
+			resource:    resource,
+			name:        sr.GetServiceName(r),
+			serviceName: r.Header.Get("x-service-name"),
+		}
+	})
+	if state == nil {
+		return false
+	}
+	if ended {
+		scopeSpans := rsb.scopeSpans(event.Attrs.Release, event.Attrs.Environment, "")
+		sr.fillSessionSpan(scopeSpans.Spans().AppendEmpty(), state)
+	}
+	return true
+}
+
+func (sr *sentrytraceReceiver) fillSessionSpan(span ptrace.Span, state *sessionState) {
+	span.SetTraceID(sr.GenerateTraceID(removeHyphens(state.sid)))
+	if sid := removeHyphens(state.sid); len(sid) >= 16 {
+		span.SetSpanID(sr.GenerateSpanId(sid[0:16]))
+	}
+	span.SetName("Session " + state.sid)
+	span.SetKind(ptrace.SpanKindClient)
+	end := state.lastUpdate
+	if state.duration != nil {
+		end = state.started.Add(time.Duration(*state.duration * float64(time.Second)))
+	}
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(state.started))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(end))
+
+	attrs := span.Attributes()
+	if state.clockSkew != 0 {
+		putClockSkewAttributes(attrs, state.clockSkew, shiftTimestamp(span.StartTimestamp(), -state.clockSkew), shiftTimestamp(span.EndTimestamp(), -state.clockSkew))
+	}
+	attrs.PutInt("sentry.envelop.type.int", models.ENVELOP_TYPE_SESSION)
+	attrs.PutStr("sentry.envelop.type", "session")
+	putNotEmptyStr(attrs, "name", state.name)
+	putNotEmptyStr(attrs, "service.name", state.serviceName)
+	attrs.PutStr("session.status", state.status)
+	attrs.PutStr("session.outcome", state.outcome())
+	attrs.PutInt("session.errors", int64(state.errors))
+	attrs.PutDouble("session.duration", end.Sub(state.started).Seconds())
+	if state.timedOut {
+		attrs.PutBool("session.timed_out", true)
+	}
+	if state.outcome() != "healthy" {
+		span.Status().SetCode(ptrace.StatusCodeError)
+	}
+}
+
+func (sr *sentrytraceReceiver) expireSessions(ctx context.Context) {
+	// the duration is checked by Config.Validate
+	timeout, _ := time.ParseDuration(sr.config.SessionsCfg.Timeout)
+	ticker := time.NewTicker(timeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, state := range sr.sessionTracker.expired(time.Now()) {
+				td := ptrace.NewTraces()
+				resourceSpans := td.ResourceSpans().AppendEmpty()
+				state.resource.CopyTo(resourceSpans.Resource())
+				sr.fillSessionSpan(resourceSpans.ScopeSpans().AppendEmpty().Spans().AppendEmpty(), state)
+				if err := sr.consumeTraces(context.Background(), td); err != nil {
+					sr.logger.Sugar().Errorf("SentryReceiver : Error sending timed out session %v : %+v", state.sid, err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	telemetryScopeName = "github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver"
+	// protects from the unbounded cardinality of service_name attribute, which is taken from the request
+	maxTrackedServices = 1000
+	otherServiceName   = "other"
+)
+
+// Reasons of the skipped envelope items
+const (
+	skipReasonEmpty               = "empty"
+	skipReasonDuplicate           = "duplicate"
+	skipReasonUnsupported         = "unsupported"
+	skipReasonAttachmentsDisabled = "attachments_disabled"
+	skipReasonTooLarge            = "too_large"
+	skipReasonStoreError          = "store_error"
+	skipReasonStoreFull           = "store_full"
+	skipReasonNoEventId           = "no_event_id"
+	skipReasonSampled             = "sampled"
+	skipReasonDeduplicated        = "deduplicated"
+	skipReasonSendFailed          = "send_failed"
+)
+
+// Item types of the Sentry envelopes. Other item types are reported as "other",
+// because the item type is taken from the request.
+var knownItemTypes = map[string]bool{
+	"event":            true,
+	"transaction":      true,
+	"session":          true,
+	"sessions":         true,
+	"span":             true,
+	"statsd":           true,
+	"metric_buckets":   true,
+	"feedback":         true,
+	"user_report":      true,
+	"attachment":       true,
+	"client_report":    true,
+	"replay_event":     true,
+	"replay_video":     true,
+	"replay_recording": true,
+	"profile":          true,
+	"profile_chunk":    true,
+	"check_in":         true,
+	"otel_log":         true,
+	"log":              true,
+}
+
+var knownContentEncodings = map[string]bool{
+	"identity": true,
+	"gzip":     true,
+	"deflate":  true,
+	"zlib":     true,
+	"br":       true,
+	"zstd":     true,
+	"snappy":   true,
+	"lz4":      true,
+}
+
+var (
+	payloadSizeBuckets      = []float64{1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216}
+	spansPerEnvelopeBuckets = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000}
+)
+
+// receiverTelemetry contains the internal metrics of the receiver, which are exposed
+// together with the other metrics of the collector
+type receiverTelemetry struct {
+	envelopeItems    metric.Int64Counter
+	skippedItems     metric.Int64Counter
+	parseFailures    metric.Int64Counter
+	requests         metric.Int64Counter
+	compressedSize   metric.Int64Histogram
+	decompressedSize metric.Int64Histogram
+	envelopeSpans    metric.Int64Histogram
+	projectsReloads  metric.Int64Counter
+	correlatedErrors metric.Int64Counter
+	originRejections metric.Int64Counter
+
+	servicesMu sync.Mutex
+	services   map[string]bool
+}
+
+func newReceiverTelemetry(settings component.TelemetrySettings) (*receiverTelemetry, error) {
+	meter := settings.MeterProvider.Meter(telemetryScopeName)
+	rt := &receiverTelemetry{services: make(map[string]bool)}
+	var err error
+	if rt.envelopeItems, err = meter.Int64Counter("otelcol_sentryreceiver_envelope_items",
+		metric.WithDescription("Number of the received envelope items by item type"),
+		metric.WithUnit("{items}")); err != nil {
+		return nil, err
+	}
+	if rt.skippedItems, err = meter.Int64Counter("otelcol_sentryreceiver_skipped_items",
+		metric.WithDescription("Number of the envelope items, which are not processed, by item type and reason"),
+		metric.WithUnit("{items}")); err != nil {
+		return nil, err
+	}
+	if rt.parseFailures, err = meter.Int64Counter("otelcol_sentryreceiver_parse_failures",
+		metric.WithDescription("Number of the envelopes, which can not be parsed, by reason"),
+		metric.WithUnit("{envelopes}")); err != nil {
+		return nil, err
+	}
+	if rt.requests, err = meter.Int64Counter("otelcol_sentryreceiver_requests",
+		metric.WithDescription("Number of the requests by service and response status code"),
+		metric.WithUnit("{requests}")); err != nil {
+		return nil, err
+	}
+	if rt.compressedSize, err = meter.Int64Histogram("otelcol_sentryreceiver_compressed_size",
+		metric.WithDescription("Size of the request body as it is received, by Content-Encoding"),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(payloadSizeBuckets...)); err != nil {
+		return nil, err
+	}
+	if rt.decompressedSize, err = meter.Int64Histogram("otelcol_sentryreceiver_decompressed_size",
+		metric.WithDescription("Size of the envelope after decompression"),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(payloadSizeBuckets...)); err != nil {
+		return nil, err
+	}
+	if rt.envelopeSpans, err = meter.Int64Histogram("otelcol_sentryreceiver_envelope_spans",
+		metric.WithDescription("Number of the spans produced from one envelope"),
+		metric.WithUnit("{spans}"),
+		metric.WithExplicitBucketBoundaries(spansPerEnvelopeBuckets...)); err != nil {
+		return nil, err
+	}
+	if rt.projectsReloads, err = meter.Int64Counter("otelcol_sentryreceiver_projects_reloads",
+		metric.WithDescription("Number of the reloads of the projects file by result"),
+		metric.WithUnit("{reloads}")); err != nil {
+		return nil, err
+	}
+	if rt.correlatedErrors, err = meter.Int64Counter("otelcol_sentryreceiver_correlated_errors",
+		metric.WithDescription("Number of the held error events by result of the correlation with transactions"),
+		metric.WithUnit("{events}")); err != nil {
+		return nil, err
+	}
+	if rt.originRejections, err = meter.Int64Counter("otelcol_sentryreceiver_origin_rejections",
+		metric.WithDescription("Number of the requests with not allowed origin by project, request type and action"),
+		metric.WithUnit("{requests}")); err != nil {
+		return nil, err
+	}
+	return rt, nil
+}
+
+func (rt *receiverTelemetry) recordItem(ctx context.Context, itemType string) {
+	rt.envelopeItems.Add(ctx, 1, metric.WithAttributes(attribute.String("item_type", normalizeItemType(itemType))))
+}
+
+func (rt *receiverTelemetry) recordSkippedItem(ctx context.Context, itemType string, reason string) {
+	rt.skippedItems.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("item_type", normalizeItemType(itemType)),
+		attribute.String("reason", reason),
+	))
+}
+
+func (rt *receiverTelemetry) recordParseFailure(ctx context.Context, reason string, itemType string) {
+	rt.parseFailures.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("reason", reason),
+		attribute.String("item_type", normalizeItemType(itemType)),
+	))
+}
+
+func (rt *receiverTelemetry) recordDecompressedSize(ctx context.Context, size int) {
+	rt.decompressedSize.Record(ctx, int64(size))
+}
+
+func (rt *receiverTelemetry) recordEnvelopeSpans(ctx context.Context, spanCount int) {
+	rt.envelopeSpans.Record(ctx, int64(spanCount))
+}
+
+func (rt *receiverTelemetry) recordProjectsReload(ctx context.Context, success bool) {
+	result := "success"
+	if !success {
+		result = "failure"
+	}
+	rt.projectsReloads.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+}
+
+func (rt *receiverTelemetry) recordCorrelatedErrors(ctx context.Context, count int, correlated bool) {
+	result := "correlated"
+	if !correlated {
+		result = "expired"
+	}
+	rt.correlatedErrors.Add(ctx, int64(count), metric.WithAttributes(attribute.String("result", result)))
+}
+
+func (rt *receiverTelemetry) recordOriginRejection(ctx context.Context, projectId string, requestType string, action string) {
+	rt.originRejections.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("project_id", projectId),
+		attribute.String("request_type", requestType),
+		attribute.String("action", action),
+	))
+}
+
+func (rt *receiverTelemetry) recordRequest(ctx context.Context, serviceName string, contentEncoding string, statusCode int, bodySize int64) {
+	rt.requests.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("service_name", rt.trackedServiceName(serviceName)),
+		attribute.String("status_code", strconv.Itoa(statusCode)),
+	))
+	contentEncoding = strings.ToLower(contentEncoding)
+	if contentEncoding == "" {
+		contentEncoding = "identity"
+	} else if !knownContentEncodings[contentEncoding] {
+		contentEncoding = "other"
+	}
+	rt.compressedSize.Record(ctx, bodySize, metric.WithAttributes(attribute.String("content_encoding", contentEncoding)))
+}
+
+func normalizeItemType(itemType string) string {
+	if itemType == "" || knownItemTypes[itemType] {
+		return itemType
+	}
+	return "other"
+}
+
+// trackedServiceName returns the service name, if the limit of the tracked services is not reached yet
+func (rt *receiverTelemetry) trackedServiceName(serviceName string) string {
+	rt.servicesMu.Lock()
+	defer rt.servicesMu.Unlock()
+	if rt.services[serviceName] {
+		return serviceName
+	}
+	if len(rt.services) >= maxTrackedServices {
+		return otherServiceName
+	}
+	rt.services[serviceName] = true
+	return serviceName
+}
+
+// instrumentHandler wraps the http handler of the server to get the request body size
+// before the decompression and the response status code
+func (sr *sentrytraceReceiver) instrumentHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentEncoding := r.Header.Get("Content-Encoding")
+		body := &countingReadCloser{ReadCloser: r.Body}
+		r.Body = body
+		sw := &statusRecordingWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		sr.telemetry.recordRequest(r.Context(), sr.GetServiceName(r), contentEncoding, sw.statusCode, body.count)
+	})
+}
+
+type countingReadCloser struct {
+	io.ReadCloser
+	count int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.count += int64(n)
+	return n, err
+}
+
+type statusRecordingWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (w *statusRecordingWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.statusCode = statusCode
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
@@ -0,0 +1,189 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"container/list"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+)
+
+const (
+	tenantAttributeName = "tenant.id"
+	// protects from the unbounded growth of the quotas state, when tenants are taken from the request headers
+	maxTrackedTenants = 10000
+)
+
+// resolveTenant evaluates the tenant of the request. The sources are checked in the order below:
+// the tenant header, the tenant of the Sentry project (from the request path or the envelope DSN),
+// the segment of the request path and the default tenant.
+func (sr *sentrytraceReceiver) resolveTenant(r *http.Request, envlp *models.EnvelopEventParseResult) string {
+	tenantCfg := sr.config.TenantCfg
+	projectTenant, hasProjectTenant := tenantCfg.ProjectTenants[getProjectId(r, envlp)]
+	if tenantCfg.Header != "" {
+		if tenant := r.Header.Get(tenantCfg.Header); tenant != "" {
+			if sr.isKnownTenant(tenant) || (hasProjectTenant && tenant == projectTenant) {
+				return tenant
+			}
+			sr.logger.Sugar().Debugf("Tenant %v from %v header is not configured, the header is ignored", tenant, tenantCfg.Header)
+		}
+	}
+	if hasProjectTenant {
+		return projectTenant
+	}
+	if tenantCfg.PathSegment > 0 {
+		pathElements := strings.Split(strings.Trim(r.URL.Path, "/ "), "/")
+		if tenantCfg.PathSegment <= len(pathElements) && pathElements[tenantCfg.PathSegment-1] != "" {
+			return pathElements[tenantCfg.PathSegment-1]
+		}
+	}
+	return tenantCfg.DefaultTenant
+}
+
+// isKnownTenant checks, that the tenant is listed in allowed-tenants or in quotas. The tenant header is set by
+// the client, so the unknown tenants are not accepted, otherwise each request could get its own quota.
+func (sr *sentrytraceReceiver) isKnownTenant(tenant string) bool {
+	if _, ok := sr.config.TenantCfg.Quotas[tenant]; ok {
+		return true
+	}
+	for _, allowed := range sr.config.TenantCfg.AllowedTenants {
+		if allowed == tenant {
+			return true
+		}
+	}
+	return false
+}
+
+// getProjectId returns the Sentry project id from the path /api/<project_id>/envelope/ or from the DSN of the envelope header
+func getProjectId(r *http.Request, envlp *models.EnvelopEventParseResult) string {
+	pathElements := strings.Split(strings.Trim(r.URL.Path, "/ "), "/")
+	for i := 0; i+1 < len(pathElements); i++ {
+		if pathElements[i] == "api" {
+			return pathElements[i+1]
+		}
+	}
+	if envlp.EnvelopEventHeader.Dsn != "" {
+		dsn, err := url.Parse(envlp.EnvelopEventHeader.Dsn)
+		if err == nil {
+			return strings.Trim(dsn.Path[strings.LastIndex(dsn.Path, "/")+1:], " ")
+		}
+	}
+	return ""
+}
+
+// tenantQuotas keeps the limiters of the tenants. When the limit of the tracked tenants is reached,
+// the least recently used limiter is evicted.
+type tenantQuotas struct {
+	sync.Mutex
+	limiters map[string]*list.Element
+	order    *list.List
+}
+
+type tenantLimiter struct {
+	tenant   string
+	requests *tokenBucket
+	bytes    *tokenBucket
+}
+
+// tokenBucket allows the request while there are tokens in the bucket. The cost of the request
+// is taken even if it is greater than the rest of tokens, so the big envelope is accepted,
+// but the next requests are rejected until the bucket is refilled.
+type tokenBucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, now time.Time) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	return &tokenBucket{rate: rate, tokens: rate, last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.rate, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// retryAfter returns the time until the bucket has tokens again
+func (b *tokenBucket) retryAfter() time.Duration {
+	if b.tokens > 0 {
+		return 0
+	}
+	return time.Duration((-b.tokens/b.rate)*float64(time.Second)) + time.Second
+}
+
+func newTenantQuotas() *tenantQuotas {
+	return &tenantQuotas{limiters: make(map[string]*list.Element), order: list.New()}
+}
+
+// allow checks the requests and bytes quotas of the tenant and returns the name of the exceeded quota
+// and the time after which the tenant can retry
+func (q *tenantQuotas) allow(tenant string, quota TenantQuotaConfig, size int, now time.Time) (string, time.Duration) {
+	if quota.RequestsPerSecond <= 0 && quota.BytesPerSecond <= 0 {
+		return "", 0
+	}
+	q.Lock()
+	defer q.Unlock()
+	var limiter *tenantLimiter
+	if element, ok := q.limiters[tenant]; ok {
+		q.order.MoveToBack(element)
+		limiter = element.Value.(*tenantLimiter)
+	} else {
+		if q.order.Len() >= maxTrackedTenants {
+			oldest := q.order.Front()
+			delete(q.limiters, q.order.Remove(oldest).(*tenantLimiter).tenant)
+		}
+		limiter = &tenantLimiter{
+			tenant:   tenant,
+			requests: newTokenBucket(quota.RequestsPerSecond, now),
+			bytes:    newTokenBucket(quota.BytesPerSecond, now),
+		}
+		q.limiters[tenant] = q.order.PushBack(limiter)
+	}
+	if limiter.requests != nil {
+		limiter.requests.refill(now)
+		if limiter.requests.tokens <= 0 {
+			return "requests", limiter.requests.retryAfter()
+		}
+	}
+	if limiter.bytes != nil {
+		limiter.bytes.refill(now)
+		if limiter.bytes.tokens <= 0 {
+			return "bytes", limiter.bytes.retryAfter()
+		}
+	}
+	if limiter.requests != nil {
+		limiter.requests.tokens--
+	}
+	if limiter.bytes != nil {
+		limiter.bytes.tokens -= float64(size)
+	}
+	return "", 0
+}
+
+func (sr *sentrytraceReceiver) getTenantQuota(tenant string) TenantQuotaConfig {
+	if quota, ok := sr.config.TenantCfg.Quotas[tenant]; ok {
+		return quota
+	}
+	return sr.config.TenantCfg.DefaultQuota
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTenantQuotasAllow(t *testing.T) {
+	type request struct {
+		after      time.Duration
+		size       int
+		quota      string
+		retryAfter time.Duration
+	}
+	tests := []struct {
+		name     string
+		quota    TenantQuotaConfig
+		requests []request
+	}{
+		{
+			name:  "no quota",
+			quota: TenantQuotaConfig{},
+			requests: []request{
+				{size: 1 << 30}, {size: 1 << 30}, {size: 1 << 30},
+			},
+		},
+		{
+			name:  "requests quota",
+			quota: TenantQuotaConfig{RequestsPerSecond: 2},
+			requests: []request{
+				{size: 10},
+				{size: 10},
+				{size: 10, quota: "requests", retryAfter: time.Second},
+				{after: 500 * time.Millisecond, size: 10},
+				{after: 500 * time.Millisecond, size: 10, quota: "requests", retryAfter: time.Second},
+			},
+		},
+		{
+			name:  "the big envelope is accepted, but the next ones wait until the bucket is refilled",
+			quota: TenantQuotaConfig{BytesPerSecond: 100},
+			requests: []request{
+				{size: 300},
+				{size: 1, quota: "bytes", retryAfter: 3 * time.Second},
+				{after: 2 * time.Second, size: 1, quota: "bytes", retryAfter: time.Second},
+				{after: 3 * time.Second, size: 1},
+			},
+		},
+		{
+			name:  "the bucket is not refilled above the rate",
+			quota: TenantQuotaConfig{RequestsPerSecond: 1},
+			requests: []request{
+				{after: time.Hour},
+				{after: time.Hour, quota: "requests", retryAfter: time.Second},
+			},
+		},
+		{
+			name:  "the requests quota is checked before the bytes quota",
+			quota: TenantQuotaConfig{RequestsPerSecond: 1, BytesPerSecond: 10},
+			requests: []request{
+				{size: 100},
+				{size: 1, quota: "requests", retryAfter: time.Second},
+				{after: time.Second, size: 1, quota: "bytes", retryAfter: 9 * time.Second},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotas := newTenantQuotas()
+			start := time.Now()
+			for i, r := range tt.requests {
+				quota, retryAfter := quotas.allow("tenant", tt.quota, r.size, start.Add(r.after))
+				if quota != r.quota || retryAfter != r.retryAfter {
+					t.Errorf("request %v : got (%q, %v), expected (%q, %v)", i, quota, retryAfter, r.quota, r.retryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestTenantQuotasAreSeparate(t *testing.T) {
+	quotas := newTenantQuotas()
+	quota := TenantQuotaConfig{RequestsPerSecond: 1}
+	now := time.Now()
+	if exceeded, _ := quotas.allow("a", quota, 1, now); exceeded != "" {
+		t.Fatalf("the first request of tenant a must be allowed, got %q", exceeded)
+	}
+	if exceeded, _ := quotas.allow("a", quota, 1, now); exceeded != "requests" {
+		t.Errorf("the second request of tenant a must be rejected, got %q", exceeded)
+	}
+	if exceeded, _ := quotas.allow("b", quota, 1, now); exceeded != "" {
+		t.Errorf("tenant b must not be limited by the quota of tenant a, got %q", exceeded)
+	}
+}
+
+func TestTenantQuotasEvictLeastRecentlyUsed(t *testing.T) {
+	quotas := newTenantQuotas()
+	quota := TenantQuotaConfig{RequestsPerSecond: 1}
+	now := time.Now()
+	quotas.allow("active", quota, 1, now)
+	for i := 1; i < maxTrackedTenants; i++ {
+		quotas.allow(strconv.Itoa(i), quota, 1, now)
+	}
+	// the active tenant becomes the most recently used one, so the next new tenant evicts tenant "1"
+	if exceeded, _ := quotas.allow("active", quota, 1, now); exceeded != "requests" {
+		t.Fatalf("the second request of the active tenant must be rejected, got %q", exceeded)
+	}
+	quotas.allow("new", quota, 1, now)
+
+	if len(quotas.limiters) != maxTrackedTenants || quotas.order.Len() != maxTrackedTenants {
+		t.Errorf("the number of tracked tenants must be limited, got %v", len(quotas.limiters))
+	}
+	if _, ok := quotas.limiters["1"]; ok {
+		t.Error("the least recently used tenant must be evicted")
+	}
+	if exceeded, _ := quotas.allow("active", quota, 1, now); exceeded != "requests" {
+		t.Errorf("the limiter of the active tenant must be kept, got %q", exceeded)
+	}
+}
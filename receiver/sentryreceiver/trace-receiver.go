@@ -14,25 +14,26 @@
 package sentryreceiver
 
 import (
-	"compress/gzip"
-	"compress/zlib"
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"math"
 	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/attachments"
 	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
 	"github.com/Netcracker/qubership-open-telemetry-collector/utils"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pdata/pcommon"
@@ -60,20 +61,36 @@ var timestampSpanDataAttributes = map[string]bool{
 }
 
 type sentrytraceReceiver struct {
-	host         component.Host
-	cancel       context.CancelFunc
-	logger       *zap.Logger
-	nextConsumer consumer.Traces
-	config       *Config
-
-	server     *http.Server
-	shutdownWG sync.WaitGroup
+	host            component.Host
+	cancel          context.CancelFunc
+	logger          *zap.Logger
+	nextConsumer    consumer.Traces
+	metricsConsumer consumer.Metrics
+	config          *Config
+
+	server       *http.Server
+	shutdownWG   sync.WaitGroup
+	startOnce    sync.Once
+	startErr     error
+	shutdownOnce sync.Once
 
 	settings receiver.Settings
 	obsrecvr *receiverhelper.ObsReport
+
+	attachmentStore attachments.Store
+	tenantQuotas    *tenantQuotas
+	projects        *projectsRegistry
+	errorCorrelator *errorCorrelator
+	sessionTracker  *sessionTracker
+	dedupCache      *dedupCache
+	telemetry       *receiverTelemetry
+
+	contextsFlattener *flattener
+	extraFlattener    *flattener
+	trustedProxies    []netip.Prefix
 }
 
-func newReceiver(config *Config, nextConsumer consumer.Traces, settings receiver.Settings) (*sentrytraceReceiver, error) {
+func newReceiver(config *Config, settings receiver.Settings) (*sentrytraceReceiver, error) {
 
 	obsrecvr, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
 		ReceiverID:             settings.ID,
@@ -82,21 +99,62 @@ func newReceiver(config *Config, nextConsumer consumer.Traces, settings receiver
 	if err != nil {
 		return nil, err
 	}
+	telemetry, err := newReceiverTelemetry(settings.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+	contextsFlattener, err := newFlattener(config.ContextsCfg.MaxDepth, config.ContextsCfg.Include, config.ContextsCfg.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	trustedProxies, err := parseTrustedProxies(config.UserCfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
 
 	sr := &sentrytraceReceiver{
-		nextConsumer: nextConsumer,
 		config:       config,
 		settings:     settings,
 		obsrecvr:     obsrecvr,
 		logger:       settings.Logger,
+		tenantQuotas: newTenantQuotas(),
+		telemetry:    telemetry,
+
+		contextsFlattener: contextsFlattener,
+		extraFlattener:    &flattener{maxDepth: config.EventFieldsCfg.MaxDepth},
+		trustedProxies:    trustedProxies,
+	}
+	// attribute-types are applied to the flattened extra values
+	sr.extraFlattener.putValue = sr.putJSONAttribute
+	if config.ErrorCorrelationCfg.Enabled {
+		// the duration is checked by Config.Validate
+		window, _ := time.ParseDuration(config.ErrorCorrelationCfg.Window)
+		sr.errorCorrelator = newErrorCorrelator(window, config.ErrorCorrelationCfg.MaxPendingEvents)
+	}
+	if config.SessionsCfg.TrackLifecycle {
+		// the duration is checked by Config.Validate
+		timeout, _ := time.ParseDuration(config.SessionsCfg.Timeout)
+		sr.sessionTracker = newSessionTracker(timeout, config.SessionsCfg.MaxSessions)
+	}
+	if config.DeduplicationCfg.Enabled {
+		// the duration is checked by Config.Validate
+		ttl, _ := time.ParseDuration(config.DeduplicationCfg.Ttl)
+		sr.dedupCache = newDedupCache(ttl, config.DeduplicationCfg.MaxMemory)
 	}
 	return sr, nil
 }
 
-func (sr *sentrytraceReceiver) Start(ctx context.Context, host component.Host) error {
+func (sr *sentrytraceReceiver) Start(_ context.Context, host component.Host) error {
+	sr.startOnce.Do(func() {
+		sr.startErr = sr.start(host)
+	})
+	return sr.startErr
+}
+
+func (sr *sentrytraceReceiver) start(host component.Host) error {
 	sr.host = host
-	ctx = context.Background()
-	ctx, sr.cancel = context.WithCancel(ctx)
+	ctx, cancel := context.WithCancel(context.Background())
+	sr.cancel = cancel
 
 	sr.logger.Info("SentryReceiver started")
 	if host == nil {
@@ -104,10 +162,37 @@ func (sr *sentrytraceReceiver) Start(ctx context.Context, host component.Host) e
 	}
 
 	var err error
-	sr.server, err = sr.config.ServerConfig.ToServer(ctx, host, sr.settings.TelemetrySettings, sr)
+	sr.server, err = sr.config.ServerConfig.ToServer(ctx, host, sr.settings.TelemetrySettings, sr,
+		confighttp.WithErrorHandler(serverErrorHandler),
+		confighttp.WithDecoder("br", newBrotliReader),
+		confighttp.WithDecoder("zstd", newZstdReader),
+	)
 	if err != nil {
 		return err
 	}
+	sr.server.Handler = sr.instrumentHandler(sr.server.Handler)
+
+	if sr.config.AttachmentsCfg.Directory != "" {
+		sr.attachmentStore, err = attachments.NewFileSystemStore(sr.config.AttachmentsCfg.Directory, int64(sr.config.AttachmentsCfg.MaxTotalSize))
+		if err != nil {
+			return err
+		}
+		go sr.cleanupAttachments(ctx)
+	}
+
+	if sr.config.ProjectsCfg.File != "" {
+		if err = sr.loadProjects(); err != nil {
+			return err
+		}
+		go sr.watchProjects(ctx)
+	}
+
+	if sr.errorCorrelator != nil {
+		go sr.expireHeldErrors(ctx)
+	}
+	if sr.sessionTracker != nil {
+		go sr.expireSessions(ctx)
+	}
 
 	var listener net.Listener
 	listener, err = sr.config.ServerConfig.ToListener(ctx)
@@ -126,54 +211,127 @@ func (sr *sentrytraceReceiver) Start(ctx context.Context, host component.Host) e
 	return nil
 }
 
-func (sr *sentrytraceReceiver) Shutdown(_ context.Context) error {
-	sr.logger.Info("SentryReceiver is shutdown")
+func (sr *sentrytraceReceiver) Shutdown(ctx context.Context) error {
+	sr.shutdownOnce.Do(func() {
+		if sr.cancel != nil {
+			sr.cancel()
+		}
+		// the server is stopped first, so that no errors are held after the flush
+		if sr.server != nil {
+			if err := sr.server.Shutdown(ctx); err != nil {
+				sr.logger.Sugar().Errorf("SentryReceiver : Error stopping the server : %+v", err)
+			}
+			sr.shutdownWG.Wait()
+		}
+		if sr.errorCorrelator != nil {
+			sr.flushHeldErrors(ctx, true)
+		}
+		removeReceiver(sr.config)
+		sr.logger.Info("SentryReceiver is shutdown")
+	})
 
 	return nil
 }
 
 func (sr *sentrytraceReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	receivedAt := time.Now()
 
-	ctx = sr.obsrecvr.StartTracesOp(ctx)
+	if r.Method == http.MethodOptions {
+		sr.handlePreflight(w, r)
+		return
+	}
 
-	pr := processBodyIfNecessary(r)
-	slurp, _ := io.ReadAll(pr)
-	if c, ok := pr.(io.Closer); ok {
-		_ = c.Close()
+	if !isSupportedContentType(r.Header.Get("Content-Type")) {
+		writeErrorResponse(w, http.StatusUnsupportedMediaType, fmt.Sprintf("unsupported Content-Type: %v", r.Header.Get("Content-Type")))
+		return
 	}
+
+	slurp, err := io.ReadAll(r.Body)
 	_ = r.Body.Close()
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeErrorResponse(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("envelope exceeds the maximum size of %v bytes", maxBytesErr.Limit))
+			return
+		}
+		sr.logger.Sugar().Errorf("Error reading request body : %+v", err)
+		writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("failed to read envelope: %v", err))
+		return
+	}
 
-	var td ptrace.Traces
-	var err error
-	envlp, err := sr.ParseEnvelopEvent(string(slurp))
+	sr.telemetry.recordDecompressedSize(ctx, len(slurp))
+
+	envlp, err := sr.ParseEnvelopEvent(ctx, string(slurp))
 	if err != nil {
+		sr.recordParseFailure(ctx, err)
 		sr.logger.Sugar().Errorf("Error parsing envelop : %+v", err)
-		w.WriteHeader(http.StatusNotAcceptable)
-		w.Write([]byte("{}"))
+		writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid envelope: %v", err))
 		return
 	}
-	td, err = sr.toTraceSpans(envlp, r)
 
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	envlp.ClockSkew = sr.getClockSkew(envlp, receivedAt)
+
+	projectId := getProjectId(r, envlp)
+	project := sr.projects.get(projectId)
+	if !sr.allowOrigin(ctx, w, r, projectId, project) {
+		return
+	}
+	r = withProjectSettings(r, project)
+	if !isEnvelopeSampled(envlp, project) {
+		sr.recordSkippedItems(ctx, envlp, skipReasonSampled)
+		writeSuccessResponse(w, envlp)
 		return
 	}
 
-	sr.logger.Sugar().Debugf("For %v got trace with %v SpanCount() : %+v", envlp.EnvelopTypeHeader.Type, td.SpanCount(), td)
+	envlp.Tenant = sr.resolveTenant(r, envlp)
+	if quota, retryAfter := sr.tenantQuotas.allow(envlp.Tenant, sr.getTenantQuota(envlp.Tenant), len(slurp), time.Now()); quota != "" {
+		sr.logger.Sugar().Debugf("Envelope of tenant %v is rejected : %v quota is exceeded", envlp.Tenant, quota)
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+		writeErrorResponse(w, http.StatusTooManyRequests, fmt.Sprintf("tenant %v exceeded the %v quota", envlp.Tenant, quota))
+		return
+	}
 
-	consumerErr := sr.nextConsumer.ConsumeTraces(ctx, td)
+	dedupKey, duplicate := sr.isDuplicate(projectId, envlp)
+	if duplicate {
+		sr.logger.Sugar().Debugf("Envelope %v is dropped as a duplicate", dedupKey)
+		sr.recordSkippedItems(ctx, envlp, skipReasonDeduplicated)
+		writeSuccessResponse(w, envlp)
+		return
+	}
 
-	sr.obsrecvr.EndTracesOp(ctx, "sentryReceiverTagValue", td.SpanCount(), consumerErr)
-	if consumerErr == nil {
-		if envlp.EnvelopType == models.ENVELOP_TYPE_SESSION {
-			w.Write([]byte("{}"))
-		} else {
-			w.Write([]byte(fmt.Sprintf("{\"id\": \"%v\"}", envlp.EnvelopEventHeader.EventID)))
+	sr.storeAttachments(ctx, envlp)
+
+	consumerErr := sr.consumeMetrics(ctx, envlp, r)
+	if consumerErr == nil && sr.nextConsumer != nil && envlp.HasTraceData() {
+		td, err := sr.toTraceSpans(envlp, r)
+		if err != nil {
+			sr.forgetDuplicate(dedupKey)
+			writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid event: %v", err))
+			return
 		}
+		applyClockSkew(td, envlp.ClockSkew)
+
+		sr.logger.Sugar().Debugf("For %v got trace with %v SpanCount() : %+v", envlp.EnvelopTypeHeader.Type, td.SpanCount(), td)
+		sr.telemetry.recordEnvelopeSpans(ctx, td.SpanCount())
+
+		// the envelope with the session update, which doesn't end the session, has no spans
+		if td.ResourceSpans().Len() > 0 {
+			if send, correlated := sr.correlateErrors(ctx, envlp, td); send {
+				consumerErr = sr.consumeTraces(ctx, td)
+				// the SDK retries the transaction, but the attached errors are not held anymore
+				if consumerErr != nil && correlated > 0 {
+					sr.recordLostErrors(ctx, correlated, consumerErr)
+				}
+			}
+		}
+	}
+	if consumerErr == nil {
+		writeSuccessResponse(w, envlp)
 		return
 	}
 	sr.logger.Sugar().Errorf("Consumer error : %+v", consumerErr)
+	sr.forgetDuplicate(dedupKey)
 
 	if consumererror.IsPermanent(consumerErr) {
 		w.WriteHeader(http.StatusBadRequest)
@@ -184,68 +342,50 @@ func (sr *sentrytraceReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-func processBodyIfNecessary(req *http.Request) io.Reader {
-	switch req.Header.Get("Content-Encoding") {
-	default:
-		return req.Body
-	case "gzip":
-		return gunzippedBodyIfPossible(req.Body)
-	case "deflate", "zlib":
-		return zlibUncompressedbody(req.Body)
-	}
-}
-
-func gunzippedBodyIfPossible(r io.Reader) io.Reader {
-	gzr, err := gzip.NewReader(r)
-	if err != nil {
-		return r
-	}
-	return gzr
-}
-
-func zlibUncompressedbody(r io.Reader) io.Reader {
-	zr, err := zlib.NewReader(r)
-	if err != nil {
-		return r
-	}
-	return zr
+func (sr *sentrytraceReceiver) consumeTraces(ctx context.Context, td ptrace.Traces) error {
+	tracesCtx := sr.obsrecvr.StartTracesOp(ctx)
+	err := sr.nextConsumer.ConsumeTraces(tracesCtx, td)
+	sr.obsrecvr.EndTracesOp(tracesCtx, "sentryReceiverTagValue", td.SpanCount(), err)
+	return err
 }
 
 func (sr *sentrytraceReceiver) toTraceSpans(envlp *models.EnvelopEventParseResult, r *http.Request) (reqs ptrace.Traces, err error) {
 	traces := ptrace.NewTraces()
-	resourceSpan := traces.ResourceSpans().AppendEmpty()
-	resource := resourceSpan.Resource()
-	sr.fillResource(&resource, envlp, r)
-	scopeSpans := resourceSpan.ScopeSpans().AppendEmpty()
+	if envlp.EnvelopType == models.ENVELOP_TYPE_EVENT {
+		for _, event := range envlp.Events {
+			// the span id of the event is taken from the first 16 characters of the event id
+			if len(event.EventId) < 16 {
+				return traces, fmt.Errorf("event_id %q is too short, at least 16 characters are expected", event.EventId)
+			}
+		}
+	}
+	rsb := sr.newResourceSpansBuilder(traces, envlp, r)
 	if envlp.EnvelopType == models.ENVELOP_TYPE_SESSION {
-		sr.appendScopeSpansForSessionEvent(&scopeSpans, envlp, r)
+		sr.appendScopeSpansForSessionEvent(rsb, envlp, r)
 	} else {
-		sr.appendScopeSpans(&scopeSpans, envlp, r)
+		sr.appendScopeSpans(rsb, envlp, r)
 	}
+	sr.appendScopeSpansForStandaloneSpans(rsb, envlp, r)
+	sr.appendScopeSpansForFeedbacks(rsb, envlp, r)
 	return traces, nil
 }
 
-func (sr *sentrytraceReceiver) fillResource(resource *pcommon.Resource, envlp *models.EnvelopEventParseResult, r *http.Request) {
-	attrs := resource.Attributes()
-	attrs.PutStr(conventions.AttributeTelemetrySDKName, envlp.EnvelopEventHeader.SdkInfo.Name)
-	attrs.PutStr(conventions.AttributeServiceName, sr.GetServiceName(r))
-	attrs.PutStr("trace.source.type", "sentry")
-}
-
-func (sr *sentrytraceReceiver) appendScopeSpans(scopeSpans *ptrace.ScopeSpans, envlp *models.EnvelopEventParseResult, r *http.Request) {
+func (sr *sentrytraceReceiver) appendScopeSpans(rsb *resourceSpansBuilder, envlp *models.EnvelopEventParseResult, r *http.Request) {
 	for _, event := range envlp.Events {
+		scopeSpans := rsb.scopeSpans(event.Release, event.Environment, event.Platform)
 		rootSpan := scopeSpans.Spans().AppendEmpty()
 		var startTime, endTime time.Time
 		rootSpan.SetTraceID(sr.GenerateTraceID(event.Contexts.Trace.TraceID))
 		eventTransaction := event.Transaction
 		eventTransactionPath := sr.removeIdFromURL(eventTransaction)
+		mobile := isMobileEvent(event, envlp)
 		if envlp.EnvelopType == models.ENVELOP_TYPE_TRANSACTION {
 			rootSpan.SetName(eventTransactionPath + " " + event.Contexts.Trace.Op)
 			rootSpan.SetSpanID(sr.GenerateSpanId(event.Contexts.Trace.SpanID))
-			startTime = GetUnixTimeFromFloat64(event.StartTimestamp)
-			endTime = GetUnixTimeFromFloat64(event.Timestamp)
+			startTime = event.StartTimestamp.Time()
+			endTime = event.Timestamp.Time()
 		} else if envlp.EnvelopType == models.ENVELOP_TYPE_EVENT {
-			endTime = GetUnixTimeFromFloat64(event.Timestamp)
+			endTime = event.Timestamp.Time()
 			startTime = endTime
 			rootSpan.SetSpanID(sr.GenerateSpanId(event.EventId[0:16]))
 			rootSpan.SetParentSpanID(sr.GenerateSpanId(event.Contexts.Trace.SpanID))
@@ -258,26 +398,36 @@ func (sr *sentrytraceReceiver) appendScopeSpans(scopeSpans *ptrace.ScopeSpans, e
 			if level == "error" || level == "fatal" {
 				rootSpan.Status().SetCode(ptrace.StatusCodeError)
 			}
+			if mobile {
+				putNotEmptyStr(rootSpan.Attributes(), "error.kind", sr.getErrorKind(event))
+			}
+			sr.putIssueFingerprint(rootSpan.Attributes(), event)
 
 			sdk := event.Sdk.Name + "@" + event.Sdk.Version
 			if sdk != "@" {
 				rootSpan.Attributes().PutStr("sdk", sdk)
 			}
-			message := event.Message
+			message := string(event.Message)
+			if event.LogEntry != nil {
+				// logentry has priority over message as in Sentry
+				if formatted := event.LogEntry.Format(); formatted != "" {
+					message = formatted
+				}
+				putNotEmptyStr(rootSpan.Attributes(), "logentry.message", string(event.LogEntry.Message))
+				putFlattenedAttributes(rootSpan.Attributes(), "logentry.params", event.LogEntry.Params)
+			}
 			if message != "" {
-				rootSpan.Attributes().PutStr("message", string(message))
+				rootSpan.Attributes().PutStr("message", message)
 			}
-			exceptionValues := event.Exception.Values
-			if len(exceptionValues) > 0 {
-				rootSpan.Attributes().PutStr("exception.values", fmt.Sprintf("%+v", exceptionValues))
+			if len(event.Exception.Values) > 0 {
+				rootSpan.Attributes().PutStr("exception.values", event.Exception.Format())
 			}
 			contextError := event.Contexts.Error
 			if contextError.Message != "" || contextError.Name != "" || contextError.Stack != "" {
 				rootSpan.Attributes().PutStr("context.error", fmt.Sprintf("%+v", contextError))
 			}
-			timestamp := event.Timestamp
-			if timestamp != 0 {
-				rootSpan.Attributes().PutDouble("timestamp", timestamp)
+			if !event.Timestamp.IsZero() {
+				rootSpan.Attributes().PutDouble("timestamp", event.Timestamp.Seconds())
 			}
 			eventId := event.EventId
 			if eventId != "" {
@@ -291,7 +441,7 @@ func (sr *sentrytraceReceiver) appendScopeSpans(scopeSpans *ptrace.ScopeSpans, e
 			if platform != "" {
 				rootSpan.Attributes().PutStr("platform", platform)
 			}
-			userId := event.User.Id
+			userId := string(event.User.Id)
 			if userId != "" {
 				rootSpan.Attributes().PutStr("user_id", userId)
 			}
@@ -305,8 +455,9 @@ func (sr *sentrytraceReceiver) appendScopeSpans(scopeSpans *ptrace.ScopeSpans, e
 			} else {
 				rootSpan.Attributes().PutStr("category", "frontend-event")
 			}
-			userAgent := event.Request.Headers["User-Agent"]
-			if userAgent != "" {
+			// the mobile SDKs send the User-Agent of the HTTP client, not of the browser
+			userAgent := event.Request.Headers.Get("User-Agent")
+			if userAgent != "" && !mobile {
 				rootSpan.Attributes().PutStr("browser", userAgent)
 			}
 		}
@@ -348,6 +499,9 @@ func (sr *sentrytraceReceiver) appendScopeSpans(scopeSpans *ptrace.ScopeSpans, e
 		if event.Environment != "" {
 			rootSpan.Attributes().PutStr("environment", event.Environment)
 		}
+		if mobile {
+			putMobileAttributes(rootSpan.Attributes(), event.Contexts)
+		}
 
 		measurements := rootSpan.Attributes().PutEmptyMap("measurements")
 		for k, m := range event.Measurements {
@@ -358,7 +512,7 @@ func (sr *sentrytraceReceiver) appendScopeSpans(scopeSpans *ptrace.ScopeSpans, e
 		rootSpan.SetKind(ptrace.SpanKindClient)
 
 		for k, v := range event.Tags {
-			rootSpan.Attributes().PutStr("tags."+k, fmt.Sprintf("%v", v))
+			sr.putJSONAttribute(rootSpan.Attributes(), "tags."+k, v)
 		}
 
 		requestUrlStr := event.Request.URL
@@ -367,13 +521,14 @@ func (sr *sentrytraceReceiver) appendScopeSpans(scopeSpans *ptrace.ScopeSpans, e
 			if err != nil {
 				sr.logger.Sugar().Errorf("Error parsing url request %v : %+v", requestUrlStr, err)
 			} else {
-				for _, qParam := range sr.config.HttpQueryParamValuesToAttrs {
-					qValue := urlParsed.Query().Get(qParam)
+				query := getRequestQuery(urlParsed, event.Request)
+				for _, qParam := range sr.httpQueryParamValuesToAttrs(r) {
+					qValue := query.Get(qParam)
 					rootSpan.Attributes().PutStr("http.qparam."+qParam, qValue)
 					sr.logger.Sugar().Debugf("Value QParam %v with value %v is found", qParam, qValue)
 				}
-				for _, qParam := range sr.config.HttpQueryParamExistenceToAttrs {
-					qValue := urlParsed.Query().Get(qParam)
+				for _, qParam := range sr.httpQueryParamExistenceToAttrs(r) {
+					qValue := query.Get(qParam)
 					if qValue != "" {
 						qValue = "true"
 					} else {
@@ -385,8 +540,9 @@ func (sr *sentrytraceReceiver) appendScopeSpans(scopeSpans *ptrace.ScopeSpans, e
 				rootSpan.Attributes().PutStr("url_path", sr.removeIdFromURL(urlParsed.Path))
 			}
 		}
+		sr.putRequestAttributes(rootSpan.Attributes(), event.Request)
 
-		for _, contextParam := range sr.config.ContextSpanAttributesList {
+		for _, contextParam := range sr.contextSpanAttributesList(r) {
 			val := event.Contexts.AsMap[contextParam]
 			if val == nil {
 				continue
@@ -401,116 +557,128 @@ func (sr *sentrytraceReceiver) appendScopeSpans(scopeSpans *ptrace.ScopeSpans, e
 			}
 		}
 
-		breadcrumbs := rootSpan.Attributes().PutEmptySlice("breadcrumbs")
-		for _, envBr := range event.Breadcrumbs {
-			if envBr.Type == "http" {
-				breadcrumb := breadcrumbs.AppendEmpty()
-				breadcrumbMap := breadcrumb.SetEmptyMap()
-				breadcrumbMap.PutStr("level", envBr.Level)
-				breadcrumbMap.PutDouble("timestamp", envBr.Timestamp)
-				breadcrumbMap.PutStr("category", envBr.Category)
-				breadcrumbMap.PutStr("message", fmt.Sprintf("%v %v", envBr.Data["method"], envBr.Data["url"]))
-				breadcrumbMap.PutStr("status", fmt.Sprintf("%v", envBr.Data["status_code"]))
-			} else if envBr.Category == "navigation" {
-				breadcrumb := breadcrumbs.AppendEmpty()
-				breadcrumbMap := breadcrumb.SetEmptyMap()
-				breadcrumbMap.PutDouble("timestamp", envBr.Timestamp)
-				breadcrumbMap.PutStr("category", "navigation")
-				breadcrumbMap.PutStr("message", fmt.Sprintf("Browser navigation from: %v to: %v", envBr.Data["from"], envBr.Data["to"]))
-			} else if envBr.Category == "console" {
-				breadcrumb := breadcrumbs.AppendEmpty()
-				breadcrumbMap := breadcrumb.SetEmptyMap()
-				breadcrumbMap.PutStr("level", envBr.Level)
-				breadcrumbMap.PutDouble("timestamp", envBr.Timestamp)
-				breadcrumbMap.PutStr("category", "console")
-				breadcrumbMap.PutStr("message", string(envBr.Message))
-			} else {
-				breadcrumb := breadcrumbs.AppendEmpty()
-				breadcrumbMap := breadcrumb.SetEmptyMap()
-				breadcrumbMap.PutStr("category", "console")
-				breadcrumbMap.PutStr("message", string(envBr.Message))
-			}
+		if sr.config.ContextsCfg.Enabled {
+			sr.contextsFlattener.put(rootSpan.Attributes(), "contexts", event.Contexts.AsMap)
 		}
 
-		rootSpan.Attributes().PutStr(conventions.AttributeEnduserID, event.User.Id)
+		sr.putEventFields(rootSpan, event)
+		sr.appendBreadcrumbEvents(rootSpan, event.Breadcrumbs)
+
+		rootSpan.Attributes().PutStr(conventions.AttributeEnduserID, string(event.User.Id))
+		sr.putUserAttributes(rootSpan.Attributes(), event.User, r)
+		putOriginAttributes(rootSpan.Attributes(), r)
+		sr.putAttachmentsAttribute(rootSpan.Attributes(), event.EventId, envlp)
 
 		for _, sentrySpan := range event.Spans {
-			span := scopeSpans.Spans().AppendEmpty()
-			startTime := GetUnixTimeFromFloat64(sentrySpan.StartTimestamp)
-			endTime := GetUnixTimeFromFloat64(sentrySpan.Timestamp)
-			span.SetTraceID(sr.GenerateTraceID(sentrySpan.TraceId))
-			span.SetSpanID(sr.GenerateSpanId(sentrySpan.SpanId))
-			span.SetParentSpanID(sr.GenerateSpanId(sentrySpan.ParentSpanId))
-			span.SetStartTimestamp(pcommon.NewTimestampFromTime(startTime))
-			span.SetEndTimestamp(pcommon.NewTimestampFromTime(endTime))
-			span.SetName(sentrySpan.Op)
-
-			var httpStatusCode string
-			if sentrySpan.Data != nil {
-				httpStatusCode = fmt.Sprintf("%v", sentrySpan.Data["http.response.status_code"])
-			}
-			if httpStatusCode != "" {
-				httpStatusCodeInt, err := strconv.ParseInt(httpStatusCode, 10, 64)
-				if err != nil {
-					span.Status().SetCode(ptrace.StatusCodeUnset)
-				} else {
-					if httpStatusCodeInt < 400 {
-						span.Status().SetCode(ptrace.StatusCodeOk)
-					} else {
-						span.Status().SetCode(ptrace.StatusCodeError)
-					}
-				}
+			sr.fillSpan(scopeSpans.Spans().AppendEmpty(), sentrySpan)
+		}
+	}
+}
+
+func (sr *sentrytraceReceiver) fillSpan(span ptrace.Span, sentrySpan models.EventSpan) {
+	startTime := sentrySpan.StartTimestamp.Time()
+	endTime := sentrySpan.Timestamp.Time()
+	span.SetTraceID(sr.GenerateTraceID(sentrySpan.TraceId))
+	span.SetSpanID(sr.GenerateSpanId(sentrySpan.SpanId))
+	span.SetParentSpanID(sr.GenerateSpanId(sentrySpan.ParentSpanId))
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(startTime))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(endTime))
+	span.SetName(sentrySpan.Op)
+
+	var httpStatusCode string
+	if sentrySpan.Data != nil {
+		httpStatusCode = fmt.Sprintf("%v", sentrySpan.Data["http.response.status_code"])
+	}
+	if httpStatusCode != "" {
+		httpStatusCodeInt, err := strconv.ParseInt(httpStatusCode, 10, 64)
+		if err != nil {
+			span.Status().SetCode(ptrace.StatusCodeUnset)
+		} else {
+			if httpStatusCodeInt < 400 {
+				span.Status().SetCode(ptrace.StatusCodeOk)
 			} else {
-				span.Status().SetCode(ptrace.StatusCodeUnset)
+				span.Status().SetCode(ptrace.StatusCodeError)
 			}
+		}
+	} else {
+		span.Status().SetCode(ptrace.StatusCodeUnset)
+	}
 
-			url := sentrySpan.Data["url"]
-			if url != nil {
-				switch urlTyped := url.(type) {
-				case string:
-					span.Attributes().PutStr("url_path", sr.removeIdFromURL(urlTyped))
-				default:
-					span.Attributes().PutStr("url_path", sr.removeIdFromURL(fmt.Sprintf("%v", urlTyped)))
-				}
-			}
+	url := sentrySpan.Data["url"]
+	if url != nil {
+		switch urlTyped := url.(type) {
+		case string:
+			span.Attributes().PutStr("url_path", sr.removeIdFromURL(urlTyped))
+		default:
+			span.Attributes().PutStr("url_path", sr.removeIdFromURL(fmt.Sprintf("%v", urlTyped)))
+		}
+	}
 
-			for k, v := range sentrySpan.Data {
-				if timestampSpanDataAttributes[k] {
-					val, ok := v.(float64)
-					if ok {
-						span.Attributes().PutDouble(k, val)
-						continue
-					}
-				}
-				switch valTyped := v.(type) {
-				case float64:
-					const epsilon = 1e-9
-					_, frac := math.Modf(valTyped)
-					frac = math.Abs(frac)
-					if frac < epsilon || frac > 1.0-epsilon {
-						span.Attributes().PutInt(k, int64(math.Round(valTyped)))
-					} else {
-						span.Attributes().PutDouble(k, valTyped)
-					}
-				case string:
-					span.Attributes().PutStr(k, valTyped)
-				default:
-					span.Attributes().PutStr(k, fmt.Sprintf("%v", v))
+	for k, v := range sentrySpan.Data {
+		if timestampSpanDataAttributes[k] {
+			// the timestamps are always doubles, even if they have no fraction
+			if number, ok := v.(json.Number); ok {
+				if val, err := number.Float64(); err == nil {
+					span.Attributes().PutDouble(k, val)
+					continue
 				}
 			}
+		}
+		sr.putJSONAttribute(span.Attributes(), k, v)
+	}
 
-			for k, v := range sentrySpan.Tags {
-				span.Attributes().PutStr("tags."+k, fmt.Sprintf("%v", v))
-			}
+	for k, v := range sentrySpan.Tags {
+		sr.putJSONAttribute(span.Attributes(), "tags."+k, v)
+	}
 
-			if sentrySpan.Origin != "" {
-				span.Attributes().PutStr("origin", sentrySpan.Origin)
-			}
-			if sentrySpan.Description != "" {
-				span.Attributes().PutStr("description", sentrySpan.Description)
-			}
+	if sentrySpan.Origin != "" {
+		span.Attributes().PutStr("origin", sentrySpan.Origin)
+	}
+	if sentrySpan.Description != "" {
+		span.Attributes().PutStr("description", sentrySpan.Description)
+	}
 
-			span.SetKind(ptrace.SpanKindClient)
+	span.SetKind(ptrace.SpanKindClient)
+}
+
+func (sr *sentrytraceReceiver) appendScopeSpansForStandaloneSpans(rsb *resourceSpansBuilder, envlp *models.EnvelopEventParseResult, r *http.Request) {
+	for _, sentrySpan := range envlp.Spans {
+		release, _ := sentrySpan.Data["sentry.release"].(string)
+		environment, _ := sentrySpan.Data["sentry.environment"].(string)
+		scopeSpans := rsb.scopeSpans(release, environment, "")
+		span := scopeSpans.Spans().AppendEmpty()
+		sr.fillSpan(span, sentrySpan)
+		attrs := span.Attributes()
+		attrs.PutInt("sentry.envelop.type.int", models.ENVELOP_TYPE_SPAN)
+		attrs.PutStr("sentry.envelop.type", "span")
+		name := sr.GetServiceName(r)
+		if name != "" {
+			attrs.PutStr("name", name)
+		}
+		serviceName := r.Header.Get("x-service-name")
+		if serviceName != "" {
+			attrs.PutStr("service.name", serviceName)
+		}
+		if sentrySpan.Op != "" {
+			attrs.PutStr("operation", sentrySpan.Op)
+		}
+		if transaction, ok := sentrySpan.Data["transaction"].(string); ok && transaction != "" {
+			attrs.PutStr("transaction", transaction)
+			attrs.PutStr("transaction_path", sr.removeIdFromURL(transaction))
+		}
+		if sentrySpan.SegmentId != "" {
+			attrs.PutStr("segment_id", sentrySpan.SegmentId)
+		}
+		attrs.PutBool("is_segment", sentrySpan.IsSegment)
+		if sentrySpan.ExclusiveTime != 0 {
+			attrs.PutDouble("exclusive_time", sentrySpan.ExclusiveTime)
+		}
+
+		measurements := attrs.PutEmptyMap("measurements")
+		for k, m := range sentrySpan.Measurements {
+			measurementMapInstance := measurements.PutEmptyMap(k)
+			measurementMapInstance.PutDouble("value", m.Value)
+			measurementMapInstance.PutStr("unit", m.Unit)
 		}
 	}
 }
@@ -547,18 +715,21 @@ func (sr *sentrytraceReceiver) evaluateLevel(event models.Event) string {
 	return ratingLevel[maxLevel]
 }
 
-func (sr *sentrytraceReceiver) appendScopeSpansForSessionEvent(scopeSpans *ptrace.ScopeSpans, envlp *models.EnvelopEventParseResult, r *http.Request) {
+func (sr *sentrytraceReceiver) appendScopeSpansForSessionEvent(rsb *resourceSpansBuilder, envlp *models.EnvelopEventParseResult, r *http.Request) {
 	for _, event := range envlp.SessionEvents {
 		sr.logger.Sugar().Debugf("Recieved session event event.Sid = %v", event.Sid)
+		if sr.sessionTracker != nil && sr.trackSession(rsb, envlp, r, event) {
+			continue
+		}
+		scopeSpans := rsb.scopeSpans(event.Attrs.Release, event.Attrs.Environment, "")
 		rootSpan := scopeSpans.Spans().AppendEmpty()
 		rootSpan.SetTraceID(sr.GenerateTraceID(removeHyphens(event.Sid)))
 		rootSpan.SetName("Session " + event.Sid)
-		rootSpan.SetSpanID(sr.GenerateSpanId(removeHyphens(event.Sid)[0:16]))
-		timestamp, err := time.Parse(time.RFC3339, event.Timestamp)
-		if err != nil {
-			sr.logger.Sugar().Errorf("Error parsing timestamp %v for session event : %+v", event.Timestamp, err)
-		} else {
-			rootSpan.SetStartTimestamp(pcommon.NewTimestampFromTime(timestamp))
+		if sid := removeHyphens(event.Sid); len(sid) >= 16 {
+			rootSpan.SetSpanID(sr.GenerateSpanId(sid[0:16]))
+		}
+		if !event.Timestamp.IsZero() {
+			rootSpan.SetStartTimestamp(pcommon.NewTimestampFromTime(event.Timestamp.Time()))
 		}
 		rootSpan.Attributes().PutInt("sentry.envelop.type.int", models.ENVELOP_TYPE_SESSION)
 		name := sr.GetServiceName(r)
@@ -576,11 +747,18 @@ func (sr *sentrytraceReceiver) appendScopeSpansForSessionEvent(scopeSpans *ptrac
 }
 
 func (sr *sentrytraceReceiver) GenerateTraceID(str string) pcommon.TraceID {
+	if str == "" {
+		return pcommon.TraceID([16]byte{})
+	}
 	data, err := hex.DecodeString(str)
 	if err != nil {
 		sr.logger.Sugar().Errorf("SentryReceiver : GenerateTraceID : Can not decode str %v to bytes : %+v", str, err)
 		return pcommon.TraceID([16]byte{})
 	}
+	if len(data) != 16 {
+		sr.logger.Sugar().Errorf("SentryReceiver : GenerateTraceID : str %v must contain 16 bytes", str)
+		return pcommon.TraceID([16]byte{})
+	}
 
 	result := (*[16]byte)(data)
 
@@ -588,22 +766,24 @@ func (sr *sentrytraceReceiver) GenerateTraceID(str string) pcommon.TraceID {
 }
 
 func (sr *sentrytraceReceiver) GenerateSpanId(str string) pcommon.SpanID {
+	if str == "" {
+		return pcommon.SpanID([8]byte{})
+	}
 	data, err := hex.DecodeString(str)
 	if err != nil {
 		sr.logger.Sugar().Errorf("SentryReceiver : GenerateSpanId : Can not decode str %v to bytes : %+v", str, err)
 		return pcommon.SpanID([8]byte{})
 	}
+	if len(data) != 8 {
+		sr.logger.Sugar().Errorf("SentryReceiver : GenerateSpanId : str %v must contain 8 bytes", str)
+		return pcommon.SpanID([8]byte{})
+	}
 
 	result := (*[8]byte)(data)
 
 	return pcommon.SpanID(*result)
 }
 
-func GetUnixTimeFromFloat64(timeFloat64 float64) time.Time {
-	sec, dec := math.Modf(timeFloat64)
-	return time.Unix(int64(sec), int64(dec*(1e9)))
-}
-
 func (sr *sentrytraceReceiver) removeIdFromURL(urlStr string) string {
 	if strings.HasPrefix(urlStr, "http://") || strings.HasPrefix(urlStr, "https://") {
 		u, err := url.Parse(urlStr)
@@ -624,6 +804,9 @@ func (sr *sentrytraceReceiver) GetServiceName(r *http.Request) string {
 	if name != "" {
 		return name
 	}
+	if settings := getProjectSettings(r); settings != nil && settings.ServiceName != "" {
+		return settings.ServiceName
+	}
 	trimmedPath := strings.Trim(r.URL.Path, "/ ")
 	pathElements := strings.Split(trimmedPath, "/")
 	if len(pathElements) > 0 {
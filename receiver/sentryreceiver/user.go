@@ -0,0 +1,123 @@
+// Copyright 2025 Qubership
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryreceiver
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/Netcracker/qubership-open-telemetry-collector/receiver/sentryreceiver/models"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// autoIpAddress is ip_address of the user, which is replaced by the IP address of the client
+const autoIpAddress = "{{auto}}"
+
+// parseTrustedProxies parses IP addresses and CIDR ranges of user.trusted-proxies
+func parseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	result := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("user.trusted-proxies contains invalid CIDR %v : %+v", proxy, err)
+			}
+			result = append(result, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("user.trusted-proxies contains invalid IP address %v : %+v", proxy, err)
+		}
+		result = append(result, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return result, nil
+}
+
+func (sr *sentrytraceReceiver) isTrustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range sr.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// getClientIp returns the IP address of the client. X-Forwarded-For and X-Real-IP headers are used only if
+// the request comes from the trusted proxy. X-Forwarded-For is read from right to left, the first address,
+// which is not a trusted proxy, is the client.
+func (sr *sentrytraceReceiver) getClientIp(r *http.Request) string {
+	remoteIp, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remoteIp = r.RemoteAddr
+	}
+	if !sr.isTrustedProxy(remoteIp) {
+		return remoteIp
+	}
+	forwarded := make([]string, 0)
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, ip := range strings.Split(header, ",") {
+			if ip = strings.TrimSpace(ip); ip != "" {
+				forwarded = append(forwarded, ip)
+			}
+		}
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		if !sr.isTrustedProxy(forwarded[i]) {
+			return forwarded[i]
+		}
+	}
+	if realIp := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIp != "" {
+		return realIp
+	}
+	if len(forwarded) > 0 {
+		return forwarded[0]
+	}
+	return remoteIp
+}
+
+// putUserAttributes adds the fields of the user, which are enabled in user settings, to the span
+func (sr *sentrytraceReceiver) putUserAttributes(attrs pcommon.Map, user models.EventUser, r *http.Request) {
+	cfg := sr.config.UserCfg
+	if cfg.Email {
+		putNotEmptyStr(attrs, "enduser.email", sr.scrubEmail(user.Email))
+	}
+	if cfg.Username {
+		putNotEmptyStr(attrs, "enduser.username", user.Username)
+	}
+	if cfg.Segment {
+		putNotEmptyStr(attrs, "enduser.segment", user.Segment)
+	}
+	if cfg.Geo {
+		putNotEmptyStr(attrs, "enduser.geo.city", user.Geo.City)
+		putNotEmptyStr(attrs, "enduser.geo.country_code", user.Geo.CountryCode)
+		putNotEmptyStr(attrs, "enduser.geo.region", user.Geo.Region)
+	}
+	if cfg.IpAddress {
+		ip := user.IpAddress
+		if strings.ReplaceAll(ip, " ", "") == autoIpAddress {
+			ip = sr.getClientIp(r)
+		}
+		putNotEmptyStr(attrs, "client.address", ip)
+	}
+}